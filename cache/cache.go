@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	cli "v/cli"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Extensions of the archives stored in the cache by runtime installers.
+var archiveExtensions = []string{".tgz", ".tar.gz", ".tar.xz", ".tar.bz2", ".zip"}
+
+// Cache settings, read from the "cache" section of the configuration file.
+type Config struct {
+	// Maximum size of the cache (i.e. "2G"), enforced after each install.
+	// No limit is enforced if empty.
+	MaxSize string `json:"maxSize"`
+}
+
+// Describes an item stored in the cache directory: either a downloaded
+// archive or a source tree left behind by an unpacked archive.
+type Entry struct {
+	Name      string
+	Path      string
+	Size      int64
+	ModTime   time.Time
+	IsArchive bool
+}
+
+func ReadConfig() (Config, error) {
+	config := Config{}
+
+	err := state.ReadConfigSection("cache", &config)
+
+	return config, err
+}
+
+func trimArchiveExtension(name string) string {
+	// Interrupted downloads are suffixed with .partial, and the URL they
+	// are downloaded from is recorded in a .partial.url file.
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".partial.url"), ".partial")
+
+	for _, extension := range archiveExtensions {
+		if trimmed, found := strings.CutSuffix(name, extension); found {
+			return trimmed
+		}
+	}
+
+	return name
+}
+
+// Returns whether the entry belongs to version <version>, given that
+// archives and source trees are named <runtime>-<version>[.<extension>],
+// or <version>[.<extension>] for implementation-qualified versions.
+func (e Entry) MatchesVersion(version string) bool {
+	name := trimArchiveExtension(e.Name)
+
+	return name == version || strings.HasSuffix(name, "-"+version)
+}
+
+func getDirectorySize(dirPath string) int64 {
+	var size int64
+
+	filepath.WalkDir(dirPath, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info, infoErr := d.Info(); infoErr == nil && !d.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size
+}
+
+// ListEntries returns the archives and source trees found in the cache
+// directory, oldest first.
+func ListEntries() ([]Entry, error) {
+	dirEntries, err := os.ReadDir(state.GetStatePath("cache"))
+
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+
+	if err != nil {
+		return []Entry{}, err
+	}
+
+	entries := []Entry{}
+
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+
+		if err != nil {
+			continue
+		}
+
+		entry := Entry{
+			Name:      dirEntry.Name(),
+			Path:      state.GetStatePath("cache", dirEntry.Name()),
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			IsArchive: !dirEntry.IsDir(),
+		}
+
+		if dirEntry.IsDir() {
+			entry.Size = getDirectorySize(entry.Path)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return a.ModTime.Compare(b.ModTime)
+	})
+
+	return entries, nil
+}
+
+func getTotalSize(entries []Entry) int64 {
+	var total int64
+
+	for _, entry := range entries {
+		total += entry.Size
+	}
+
+	return total
+}
+
+func removeEntry(entry Entry) error {
+	if err := os.RemoveAll(entry.Path); err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Removed %s (%s)\n", entry.Name, logger.FormatSize(entry.Size))
+
+	return nil
+}
+
+// Clean removes all cache entries, or only the entries matching <version>
+// if it is not empty. The entries removed are returned.
+func Clean(version string) ([]Entry, error) {
+	entries, err := ListEntries()
+
+	if err != nil {
+		return []Entry{}, err
+	}
+
+	removed := []Entry{}
+
+	for _, entry := range entries {
+		if version != "" && !entry.MatchesVersion(version) {
+			continue
+		}
+
+		if err := removeEntry(entry); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, entry)
+	}
+
+	return removed, nil
+}
+
+// Prune removes entries older than <olderThan> (if non-zero), then the oldest
+// entries until the cache fits in <maxSize> bytes (if non-zero). The entries
+// removed are returned.
+func Prune(olderThan time.Duration, maxSize int64) ([]Entry, error) {
+	entries, err := ListEntries()
+
+	if err != nil {
+		return []Entry{}, err
+	}
+
+	removed := []Entry{}
+	kept := []Entry{}
+
+	for _, entry := range entries {
+		if olderThan == 0 || time.Since(entry.ModTime) < olderThan {
+			kept = append(kept, entry)
+			continue
+		}
+
+		if err := removeEntry(entry); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, entry)
+	}
+
+	totalSize := getTotalSize(kept)
+
+	for _, entry := range kept {
+		if maxSize == 0 || totalSize <= maxSize {
+			break
+		}
+
+		if err := removeEntry(entry); err != nil {
+			return removed, err
+		}
+
+		totalSize -= entry.Size
+		removed = append(removed, entry)
+	}
+
+	return removed, nil
+}
+
+// EnforceSizeLimit prunes the oldest cache entries if the cache exceeds the
+// maximum size set in the configuration file, if any.
+func EnforceSizeLimit() error {
+	config, err := ReadConfig()
+
+	if err != nil || config.MaxSize == "" {
+		return err
+	}
+
+	maxSize, err := cli.ParseSize(config.MaxSize)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = Prune(0, maxSize)
+
+	return err
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func writeCacheEntry(t *testing.T, name string, size int, age time.Duration) {
+	entryPath := state.GetStatePath("cache", name)
+	os.MkdirAll(state.GetStatePath("cache"), 0750)
+	os.WriteFile(entryPath, make([]byte, size), 0750)
+	modTime := time.Now().Add(-age)
+	os.Chtimes(entryPath, modTime, modTime)
+}
+
+func TestListEntriesSeparatesArchivesAndSourceTrees(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	writeCacheEntry(t, "Python-3.11.4.tgz", 100, time.Hour)
+	os.MkdirAll(state.GetStatePath("cache", "Python-3.10.0"), 0750)
+	os.WriteFile(path.Join(state.GetStatePath("cache", "Python-3.10.0"), "configure"), make([]byte, 50), 0750)
+
+	entries, err := ListEntries()
+
+	if err != nil || len(entries) != 2 {
+		t.Errorf("Expected 2 entries, got %v (%v)", entries, err)
+	}
+
+	for _, entry := range entries {
+		if entry.Name == "Python-3.11.4.tgz" && (!entry.IsArchive || entry.Size != 100) {
+			t.Errorf("Unexpected archive entry: %v", entry)
+		}
+
+		if entry.Name == "Python-3.10.0" && (entry.IsArchive || entry.Size != 50) {
+			t.Errorf("Unexpected source tree entry: %v", entry)
+		}
+	}
+}
+
+func TestListEntriesNoCacheDirectory(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	entries, err := ListEntries()
+
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries, got %v (%v)", entries, err)
+	}
+}
+
+func TestCleanRemovesOnlyMatchingVersion(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	writeCacheEntry(t, "Python-3.11.4.tgz", 100, time.Hour)
+	writeCacheEntry(t, "Python-3.11.40.tgz", 100, time.Hour)
+	os.MkdirAll(state.GetStatePath("cache", "Python-3.11.4"), 0750)
+
+	removed, err := Clean("3.11.4")
+
+	if err != nil || len(removed) != 2 {
+		t.Errorf("Expected 2 entries removed, got %v (%v)", removed, err)
+	}
+
+	if _, err := os.Stat(state.GetStatePath("cache", "Python-3.11.40.tgz")); err != nil {
+		t.Errorf("Expected other versions to be kept.")
+	}
+}
+
+func TestCleanRemovesImplementationArchives(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	writeCacheEntry(t, "pypy3.10-7.3.15.tar.bz2", 100, time.Hour)
+	writeCacheEntry(t, "pypy3.9-7.3.15.tar.bz2", 100, time.Hour)
+
+	removed, err := Clean("pypy3.10-7.3.15")
+
+	if err != nil || len(removed) != 1 || removed[0].Name != "pypy3.10-7.3.15.tar.bz2" {
+		t.Errorf("Expected only pypy3.10-7.3.15.tar.bz2 removed, got %v (%v)", removed, err)
+	}
+}
+
+func TestPruneRemovesEntriesOlderThan(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	writeCacheEntry(t, "Python-3.9.0.tgz", 100, 40*24*time.Hour)
+	writeCacheEntry(t, "Python-3.11.4.tgz", 100, time.Hour)
+
+	removed, err := Prune(30*24*time.Hour, 0)
+
+	if err != nil || len(removed) != 1 || removed[0].Name != "Python-3.9.0.tgz" {
+		t.Errorf("Unexpected entries removed: %v (%v)", removed, err)
+	}
+}
+
+func TestPruneRemovesOldestEntriesOverMaxSize(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	writeCacheEntry(t, "Python-3.9.0.tgz", 100, 3*time.Hour)
+	writeCacheEntry(t, "Python-3.10.0.tgz", 100, 2*time.Hour)
+	writeCacheEntry(t, "Python-3.11.4.tgz", 100, time.Hour)
+
+	removed, err := Prune(0, 150)
+
+	if err != nil || len(removed) != 2 || removed[0].Name != "Python-3.9.0.tgz" || removed[1].Name != "Python-3.10.0.tgz" {
+		t.Errorf("Unexpected entries removed: %v (%v)", removed, err)
+	}
+}
+
+func TestEnforceSizeLimitUsesConfiguredMaxSize(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"cache": {"maxSize": "150"}}`), 0750)
+	writeCacheEntry(t, "Python-3.9.0.tgz", 100, 2*time.Hour)
+	writeCacheEntry(t, "Python-3.11.4.tgz", 100, time.Hour)
+
+	if err := EnforceSizeLimit(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(state.GetStatePath("cache", "Python-3.9.0.tgz")); !os.IsNotExist(err) {
+		t.Errorf("Expected oldest entry to be removed.")
+	}
+}
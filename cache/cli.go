@@ -0,0 +1,18 @@
+package cache
+
+import (
+	cli "v/cli"
+)
+
+func GetNamespace() cli.Namespace {
+	cacheCommands := cli.Namespace{Label: "cache"}
+	cacheCommands.AddCommand(
+		"ls", listCache, "v cache ls", "Lists cached archives and orphaned source trees.",
+	).AddCommand(
+		"clean", cleanCache, "v cache clean [version]", "Removes all cached items, or those of the given version.",
+	).AddCommand(
+		"prune", pruneCache, "v cache prune [--older-than <age>] [--max-size <size>]", "Removes old cached items or the oldest ones over the size budget.",
+	)
+
+	return cacheCommands
+}
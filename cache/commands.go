@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"errors"
+	cli "v/cli"
+	logger "v/logger"
+	state "v/state"
+)
+
+func printEntries(label string, entries []Entry) {
+	logger.InfoLogger.Println(logger.Bold(label))
+
+	if len(entries) == 0 {
+		logger.InfoLogger.Println("  None")
+		return
+	}
+
+	for _, entry := range entries {
+		logger.InfoLogger.Printf("  %-40s%12s  %s\n", entry.Name, logger.FormatSize(entry.Size), logger.FormatAge(entry.ModTime))
+	}
+}
+
+// Lists downloaded archives and source trees left behind in the cache.
+func listCache(args []string, flags cli.Flags, currentState state.State) error {
+	entries, err := ListEntries()
+
+	if err != nil {
+		return err
+	}
+
+	archives := []Entry{}
+	sourceTrees := []Entry{}
+
+	for _, entry := range entries {
+		if entry.IsArchive {
+			archives = append(archives, entry)
+		} else {
+			sourceTrees = append(sourceTrees, entry)
+		}
+	}
+
+	printEntries("Archives", archives)
+	printEntries("Orphaned source trees", sourceTrees)
+	logger.InfoLogger.Printf("Total: %s\n", logger.FormatSize(getTotalSize(entries)))
+
+	return nil
+}
+
+// Removes everything from the cache, or only what relates to the given version.
+func cleanCache(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+	version := ""
+
+	if len(positional) > 1 {
+		version = positional[1]
+	}
+
+	removed, err := Clean(version)
+
+	if err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Freed %s\n", logger.FormatSize(getTotalSize(removed)))
+	return nil
+}
+
+// Removes old entries and/or the oldest entries exceeding a size budget. If
+// no criteria is given, the maximum size set in the configuration is used.
+func pruneCache(args []string, flags cli.Flags, currentState state.State) error {
+	maxSize := flags.MaxSize
+
+	if flags.OlderThan == 0 && maxSize == 0 {
+		config, err := ReadConfig()
+
+		if err != nil {
+			return err
+		}
+
+		if config.MaxSize == "" {
+			return errors.New("Nothing to prune by. Pass --older-than and/or --max-size, or set cache.maxSize in the configuration.")
+		}
+
+		if maxSize, err = cli.ParseSize(config.MaxSize); err != nil {
+			return err
+		}
+	}
+
+	removed, err := Prune(flags.OlderThan, maxSize)
+
+	if err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Freed %s\n", logger.FormatSize(getTotalSize(removed)))
+	return nil
+}
@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"errors"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 	logger "v/logger"
 	state "v/state"
 )
@@ -13,6 +16,59 @@ type Flags struct {
 	NoCache   bool
 	Verbose   bool
 	RawOutput bool
+	// Build options.
+	Jobs          int
+	ConfigureOpts []string
+	LTO           bool
+	NoPGO         bool
+	BuildEnv      []string
+	// Source options.
+	Offline     bool
+	FromArchive string
+	GitRef      string
+	SourceDir   string
+	Patches     []string
+	// Skips installing the default packages after installs.
+	SkipDefaultPackages bool
+	// Cache options.
+	OlderThan time.Duration
+	MaxSize   int64
+	// Import options.
+	DryRun bool
+	Move   bool
+	// Version to create virtual environments from.
+	Python string
+	// Upgrade options.
+	UpdateVersionFile bool
+	UninstallOld      bool
+	// Uninstall options.
+	Yes       bool
+	Force     bool
+	All       bool
+	AllExcept []string
+	// Minimum time since a version was last used for it to be garbage-collected.
+	UnusedFor time.Duration
+	// Installs the requested version if missing (see: `v python exec`).
+	Install bool
+}
+
+// Separates v's arguments from those passed through to another command.
+const separator = "--"
+
+// Flags that expect a value, passed either as --flag=value or --flag value.
+var valueFlags = []string{
+	"--jobs",
+	"--configure-opt",
+	"--build-env",
+	"--from-archive",
+	"--git",
+	"--source-dir",
+	"--patch",
+	"--python",
+	"--all-except",
+	"--older-than",
+	"--unused-for",
+	"--max-size",
 }
 
 // Represents a CLI invocation.
@@ -48,7 +104,11 @@ func (c *CLI) ListNamespaces() []string {
 // Executes one of the registered commands if any match the provided
 // user arguments.
 func (c CLI) Run(args []string, currentState state.State) error {
-	flags := collectFlags(args)
+	flags, err := collectFlags(args)
+
+	if err != nil {
+		return err
+	}
 
 	if flags.Verbose {
 		logger.DebugLogger.SetOutput(os.Stdout)
@@ -91,17 +151,41 @@ func (c CLI) Help() {
 	}
 }
 
+// Splits a flag argument into its label and inline value (--<flag-label>=<value>), if any.
+func splitFlag(arg string) (string, string, bool) {
+	return strings.Cut(arg, "=")
+}
+
 // Traverses input arguments and extracts flags of
-// the form --<flag-label>.
-func collectFlags(args []string) Flags {
+// the form --<flag-label>. Flags expecting a value take it either
+// inline (--<flag-label>=<value>) or from the next argument. Arguments
+// after the separator (--) are left alone (see: Passthrough).
+func collectFlags(args []string) (Flags, error) {
 	collected := Flags{}
 
-	for _, arg := range args {
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+
+		if arg == separator {
+			break
+		}
+
 		if !strings.HasPrefix(arg, "--") {
 			continue
 		}
 
-		switch arg {
+		label, value, hasValue := splitFlag(arg)
+
+		if slices.Contains(valueFlags, label) && !hasValue {
+			if index+1 >= len(args) {
+				return collected, errors.New("Missing value for flag " + label)
+			}
+
+			index++
+			value = args[index]
+		}
+
+		switch label {
 		case "--verbose":
 			collected.Verbose = true
 		case "--no-cache":
@@ -110,8 +194,125 @@ func collectFlags(args []string) Flags {
 			collected.AddPath = true
 		case "--raw":
 			collected.RawOutput = true
+		case "--skip-default-packages":
+			collected.SkipDefaultPackages = true
+		case "--update-version-file":
+			collected.UpdateVersionFile = true
+		case "--uninstall-old":
+			collected.UninstallOld = true
+		case "--install":
+			collected.Install = true
+		case "--yes":
+			collected.Yes = true
+		case "--force":
+			collected.Force = true
+		case "--all":
+			collected.All = true
+		case "--all-except":
+			collected.All = true
+
+			for _, version := range strings.Split(value, ",") {
+				if version = strings.TrimSpace(version); version != "" {
+					collected.AllExcept = append(collected.AllExcept, version)
+				}
+			}
+		case "--dry-run":
+			collected.DryRun = true
+		case "--move":
+			collected.Move = true
+		case "--jobs":
+			jobs, err := strconv.Atoi(value)
+
+			if err != nil || jobs < 1 {
+				return collected, errors.New("Invalid value for --jobs, expected a positive integer: " + value)
+			}
+
+			collected.Jobs = jobs
+		case "--configure-opt":
+			collected.ConfigureOpts = append(collected.ConfigureOpts, value)
+		case "--lto":
+			collected.LTO = true
+		case "--no-pgo":
+			collected.NoPGO = true
+		case "--build-env":
+			if !strings.Contains(value, "=") {
+				return collected, errors.New("Invalid value for --build-env, expected KEY=VALUE: " + value)
+			}
+
+			collected.BuildEnv = append(collected.BuildEnv, value)
+		case "--offline":
+			collected.Offline = true
+		case "--from-archive":
+			collected.FromArchive = value
+		case "--git":
+			collected.GitRef = value
+		case "--source-dir":
+			collected.SourceDir = value
+		case "--patch":
+			collected.Patches = append(collected.Patches, value)
+		case "--python":
+			collected.Python = value
+		case "--older-than":
+			olderThan, err := ParseDuration(value)
+
+			if err != nil {
+				return collected, err
+			}
+
+			collected.OlderThan = olderThan
+		case "--unused-for":
+			unusedFor, err := ParseDuration(value)
+
+			if err != nil {
+				return collected, err
+			}
+
+			collected.UnusedFor = unusedFor
+		case "--max-size":
+			maxSize, err := ParseSize(value)
+
+			if err != nil {
+				return collected, err
+			}
+
+			collected.MaxSize = maxSize
+		}
+	}
+
+	return collected, nil
+}
+
+// Positional returns the arguments before the separator (--) that are
+// neither flags nor values consumed by flags (see: collectFlags).
+func Positional(args []string) []string {
+	positional := []string{}
+
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+
+		if arg == separator {
+			break
 		}
+
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		if label, _, hasValue := splitFlag(arg); slices.Contains(valueFlags, label) && !hasValue {
+			index++
+		}
+	}
+
+	return positional
+}
+
+// Passthrough returns the arguments following the separator (--), meant
+// for another command.
+func Passthrough(args []string) []string {
+	if index := slices.Index(args, separator); index != -1 {
+		return args[index+1:]
 	}
 
-	return collected
+	return []string{}
 }
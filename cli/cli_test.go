@@ -35,3 +35,84 @@ func TestListNamespacesReturnsAlphaOrderedLabels(t *testing.T) {
 	}
 
 }
+
+func TestCollectFlagsParsesValueFlags(t *testing.T) {
+	flags, err := collectFlags([]string{"install", "--jobs=8", "--configure-opt", "--with-pydebug", "--build-env", "CFLAGS=-O2", "3.11.4", "--lto"})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if flags.Jobs != 8 || !flags.LTO {
+		t.Errorf("Unexpected flags: %v", flags)
+	}
+
+	if !slices.Equal(flags.ConfigureOpts, []string{"--with-pydebug"}) || !slices.Equal(flags.BuildEnv, []string{"CFLAGS=-O2"}) {
+		t.Errorf("Unexpected flags: %v", flags)
+	}
+}
+
+func TestCollectFlagsReturnsErrorOnMissingValue(t *testing.T) {
+	if _, err := collectFlags([]string{"install", "--jobs"}); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestCollectFlagsReturnsErrorOnInvalidJobs(t *testing.T) {
+	if _, err := collectFlags([]string{"install", "--jobs=many"}); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestPositionalSkipsFlagsAndFlagValues(t *testing.T) {
+	positional := Positional([]string{"install", "--jobs", "8", "3.11.4", "--configure-opt=--with-lto", "--verbose"})
+
+	if !slices.Equal(positional, []string{"install", "3.11.4"}) {
+		t.Errorf("Unexpected positional arguments: %v", positional)
+	}
+}
+
+func TestCollectFlagsCollectsRepeatedPatches(t *testing.T) {
+	flags, err := collectFlags([]string{"install", "3.8.10", "--patch", "a.patch", "--patch=b.patch"})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !slices.Equal(flags.Patches, []string{"a.patch", "b.patch"}) {
+		t.Errorf("Expected both patches to be collected, got %v", flags.Patches)
+	}
+}
+
+func TestCollectFlagsAllExceptImpliesAll(t *testing.T) {
+	flags, err := collectFlags([]string{"uninstall", "--all-except", "3.11.4, 3.12.1"})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !flags.All || !slices.Equal(flags.AllExcept, []string{"3.11.4", "3.12.1"}) {
+		t.Errorf("Expected --all with exceptions, got %v %v", flags.All, flags.AllExcept)
+	}
+}
+
+func TestArgumentsAfterSeparatorArePassedThrough(t *testing.T) {
+	args := []string{"exec", "3.9.18", "--install", "--", "pytest", "--verbose", "--jobs"}
+	flags, err := collectFlags(args)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !flags.Install || flags.Verbose {
+		t.Errorf("Expected only the flags before the separator to be collected, got %+v", flags)
+	}
+
+	if positional := Positional(args); !slices.Equal(positional, []string{"exec", "3.9.18"}) {
+		t.Errorf("Unexpected positional arguments: %v", positional)
+	}
+
+	if passthrough := Passthrough(args); !slices.Equal(passthrough, []string{"pytest", "--verbose", "--jobs"}) {
+		t.Errorf("Unexpected passthrough arguments: %v", passthrough)
+	}
+}
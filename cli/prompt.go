@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	logger "v/logger"
+)
+
+// Input confirmation answers are read from.
+var PromptInput io.Reader = os.Stdin
+
+// Confirm asks <question> and returns whether the user answered yes. Any
+// other answer, including no answer at all (i.e. non-interactive input), is
+// considered a no.
+func Confirm(question string) bool {
+	logger.InfoLogger.Print(question + " [y/N] ")
+
+	answer, _ := bufio.NewReader(PromptInput).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+	testutils "v/testutils"
+)
+
+func TestConfirmAcceptsOnlyYes(t *testing.T) {
+	testutils.CaptureOutput(t)
+
+	defer func(previous io.Reader) { PromptInput = previous }(PromptInput)
+
+	for input, expected := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		PromptInput = strings.NewReader(input)
+
+		if Confirm("Proceed?") != expected {
+			t.Errorf("Expected %q to confirm: %v", input, expected)
+		}
+	}
+}
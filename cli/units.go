@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+var sizeUnits = map[string]int64{
+	"":  1,
+	"B": 1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// ParseDuration parses user-provided durations of the form <amount><unit>
+// (i.e. 30d), where the unit is one of m (minutes), h (hours), d (days)
+// or w (weeks).
+func ParseDuration(value string) (time.Duration, error) {
+	invalidErr := errors.New("Invalid duration: " + value + ". Expected a value like 12h, 30d or 2w.")
+
+	if len(value) < 2 {
+		return 0, invalidErr
+	}
+
+	unit, found := durationUnits[value[len(value)-1:]]
+	amount, err := strconv.Atoi(value[:len(value)-1])
+
+	if !found || err != nil || amount < 0 {
+		return 0, invalidErr
+	}
+
+	return time.Duration(amount) * unit, nil
+}
+
+// ParseSize parses user-provided sizes of the form <amount><unit> (i.e. 2G)
+// into bytes. Units are powers of 1024 and can be omitted for bytes.
+func ParseSize(value string) (int64, error) {
+	normalized := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(value)), "B")
+	number := strings.TrimRight(normalized, "KMGT")
+	unit, found := sizeUnits[normalized[len(number):]]
+	amount, err := strconv.ParseFloat(number, 64)
+
+	if !found || err != nil || amount < 0 {
+		return 0, errors.New("Invalid size: " + value + ". Expected a value like 500M or 2G.")
+	}
+
+	return int64(amount * float64(unit)), nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30m": 30 * time.Minute,
+		"12h": 12 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	}
+
+	for input, expected := range cases {
+		if parsed, err := ParseDuration(input); err != nil || parsed != expected {
+			t.Errorf("Expected %s to parse as %s, got %s (%v)", input, expected, parsed, err)
+		}
+	}
+}
+
+func TestParseDurationReturnsErrorOnInvalidInput(t *testing.T) {
+	for _, input := range []string{"", "d", "30", "30y", "-1d"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("Expected error parsing %s", input)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"100":  100,
+		"1K":   1024,
+		"500M": 500 * 1024 * 1024,
+		"2G":   2 * 1024 * 1024 * 1024,
+		"2gb":  2 * 1024 * 1024 * 1024,
+		"1.5G": 3 * 512 * 1024 * 1024,
+	}
+
+	for input, expected := range cases {
+		if parsed, err := ParseSize(input); err != nil || parsed != expected {
+			t.Errorf("Expected %s to parse as %d, got %d (%v)", input, expected, parsed, err)
+		}
+	}
+}
+
+func TestParseSizeReturnsErrorOnInvalidInput(t *testing.T) {
+	for _, input := range []string{"", "G", "2X", "-1G"} {
+		if _, err := ParseSize(input); err == nil {
+			t.Errorf("Expected error parsing %s", input)
+		}
+	}
+}
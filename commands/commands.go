@@ -11,8 +11,12 @@ import (
 
 var stateDirectories = []string{
 	"cache",
+	"logs",
 	"runtimes",
 	"shims",
+	"sources",
+	"tools",
+	"venvs",
 }
 
 const defaultFilePermissions = 0775
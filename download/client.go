@@ -0,0 +1,190 @@
+package download
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Client returns the HTTP client used for every network call made by v,
+// configured from the "download" section of the configuration file: timeouts,
+// proxy, trusted certificate authorities, client certificate and per-host
+// credentials. Runtimes should use it (or File and Fetch) rather than the
+// default client.
+func Client() (*http.Client, error) {
+	options, err := ReadOptions()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newHTTPClient(options)
+}
+
+func newHTTPClient(options Options) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: options.ConnectTimeout}).DialContext
+	transport.TLSHandshakeTimeout = options.ConnectTimeout
+	transport.ResponseHeaderTimeout = options.ConnectTimeout
+
+	proxy, err := getProxyFunc(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transport.Proxy = proxy
+
+	tlsConfig, err := getTLSConfig(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	credentials := map[string]netrcEntry{}
+
+	if options.Netrc {
+		if credentials, err = readNetrc(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Client{Transport: &authTransport{
+		base:        transport,
+		headers:     options.Headers,
+		credentials: credentials,
+		offline:     options.Offline,
+	}}, nil
+}
+
+// Returns the function selecting the proxy used for each request. Unless a
+// proxy is configured, HTTPS_PROXY, HTTP_PROXY and NO_PROXY are used.
+func getProxyFunc(options Options) (func(*http.Request) (*url.URL, error), error) {
+	if options.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(options.Proxy)
+
+	if err != nil {
+		return nil, errors.New("Invalid proxy URL: " + options.Proxy)
+	}
+
+	noProxy := options.NoProxy
+
+	if noProxy == nil {
+		noProxy = strings.Split(os.Getenv("NO_PROXY")+","+os.Getenv("no_proxy"), ",")
+	}
+
+	return func(request *http.Request) (*url.URL, error) {
+		if isExcludedFromProxy(request.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+
+		return proxyURL, nil
+	}, nil
+}
+
+// Returns whether <host> matches any of the NO_PROXY-style patterns in
+// <noProxy>: "*", exact hostnames or domain suffixes (example.com or
+// .example.com, both matching subdomains).
+func isExcludedFromProxy(host string, noProxy []string) bool {
+	for _, pattern := range noProxy {
+		pattern = strings.TrimSpace(pattern)
+
+		if pattern == "" {
+			continue
+		}
+
+		if pattern == "*" || host == strings.TrimPrefix(pattern, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(pattern, ".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the TLS configuration trusting the system certificate authorities
+// along with the configured ones, and presenting the client certificate, if any.
+func getTLSConfig(options Options) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if options.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		caCerts, err := os.ReadFile(options.CAFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !pool.AppendCertsFromPEM(caCerts) {
+			return nil, errors.New("No valid certificate found in " + options.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if options.ClientCert != "" || options.ClientKey != "" {
+		certificate, err := tls.LoadX509KeyPair(options.ClientCert, options.ClientKey)
+
+		if err != nil {
+			return nil, errors.New("Failed to load client certificate: " + err.Error())
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// Round-tripper adding configured headers and netrc credentials to requests
+// based on their host, and refusing requests in offline mode.
+type authTransport struct {
+	base        http.RoundTripper
+	headers     map[string]map[string]string
+	credentials map[string]netrcEntry
+	offline     bool
+}
+
+func (t *authTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if t.offline {
+		return nil, ErrOffline
+	}
+
+	host := request.URL.Hostname()
+	hostHeaders, hasHeaders := t.headers[host]
+	entry, hasCredentials := t.credentials[host]
+
+	if !hasCredentials {
+		entry, hasCredentials = t.credentials[netrcDefaultMachine]
+	}
+
+	if !hasHeaders && !hasCredentials {
+		return t.base.RoundTrip(request)
+	}
+
+	// Requests must not be modified by round-trippers.
+	request = request.Clone(request.Context())
+
+	for key, value := range hostHeaders {
+		request.Header.Set(key, value)
+	}
+
+	if hasCredentials && request.Header.Get("Authorization") == "" {
+		request.SetBasicAuth(entry.Login, entry.Password)
+	}
+
+	return t.base.RoundTrip(request)
+}
@@ -0,0 +1,121 @@
+package download
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+	testutils "v/testutils"
+)
+
+func TestParseNetrc(t *testing.T) {
+	entries := parseNetrc(`
+machine artifactory.example.com
+  login alice
+  password s3cret
+default login anonymous password guest
+`)
+
+	if entries["artifactory.example.com"] != (netrcEntry{Login: "alice", Password: "s3cret"}) {
+		t.Errorf("Unexpected entry: %v", entries["artifactory.example.com"])
+	}
+
+	if entries[netrcDefaultMachine] != (netrcEntry{Login: "anonymous", Password: "guest"}) {
+		t.Errorf("Unexpected default entry: %v", entries[netrcDefaultMachine])
+	}
+}
+
+func TestIsExcludedFromProxy(t *testing.T) {
+	noProxy := []string{"localhost", ".internal.example.com", "corp.example.com"}
+
+	cases := map[string]bool{
+		"localhost":                   true,
+		"mirror.internal.example.com": true,
+		"corp.example.com":            true,
+		"a.corp.example.com":          true,
+		"www.python.org":              false,
+		"notcorp.example.com":         false,
+	}
+
+	for host, expected := range cases {
+		if excluded := isExcludedFromProxy(host, noProxy); excluded != expected {
+			t.Errorf("Expected %s to be excluded: %t, got %t", host, expected, excluded)
+		}
+	}
+}
+
+func TestClientSendsConfiguredHeadersAndNetrcCredentials(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	netrcPath := path.Join(t.TempDir(), ".netrc")
+	os.WriteFile(netrcPath, []byte("machine 127.0.0.1 login alice password s3cret"), 0600)
+	t.Setenv("NETRC", netrcPath)
+
+	client, err := newHTTPClient(Options{
+		ConnectTimeout: time.Second,
+		Headers:        map[string]map[string]string{"127.0.0.1": {"X-Api-Key": "key"}},
+		Netrc:          true,
+	})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	client.Get(server.URL)
+
+	if received.Get("X-Api-Key") != "key" {
+		t.Errorf("Expected configured header to be sent, got %v", received)
+	}
+
+	if username, password, ok := (&http.Request{Header: received}).BasicAuth(); !ok || username != "alice" || password != "s3cret" {
+		t.Errorf("Expected netrc credentials to be sent, got %v", received)
+	}
+}
+
+func TestClientRefusesRequestsOffline(t *testing.T) {
+	client, _ := newHTTPClient(Options{Offline: true})
+
+	if _, err := client.Get("https://www.python.org"); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestClientReturnsErrorOnInvalidCAFile(t *testing.T) {
+	caFile := path.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, []byte("not a certificate"), 0600)
+
+	if _, err := newHTTPClient(Options{CAFile: caFile}); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestClientTrustsConfiguredCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := path.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, encodeCertificate(server.Certificate().Raw), 0600)
+
+	client, err := newHTTPClient(Options{ConnectTimeout: time.Second, CAFile: caFile})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := client.Get(server.URL); err != nil {
+		t.Errorf("Expected server certificate to be trusted, got %v", err)
+	}
+}
+
+func encodeCertificate(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,257 @@
+package download
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+var mockContent = bytes.Repeat([]byte("0123456789"), 1000)
+
+var testOptions = Options{ConnectTimeout: time.Second, StallTimeout: time.Second, Retries: 2}
+
+func serveMockContent(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, "Python-1.2.3.tgz", time.Now(), bytes.NewReader(mockContent))
+}
+
+func TestFileDownloadsContent(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	server := httptest.NewServer(http.HandlerFunc(serveMockContent))
+	defer server.Close()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+
+	if err := fileWithOptions(server.URL, destination, testOptions); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	content, _ := os.ReadFile(destination)
+
+	if !bytes.Equal(content, mockContent) {
+		t.Errorf("Downloaded content does not match.")
+	}
+
+	if _, err := os.Stat(destination + partialSuffix); !os.IsNotExist(err) {
+		t.Errorf("Expected partial file to be removed.")
+	}
+}
+
+func TestFileResumesPartialDownload(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	requestedRange := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedRange = r.Header.Get("Range")
+		serveMockContent(w, r)
+	}))
+	defer server.Close()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+	os.WriteFile(destination+partialSuffix, mockContent[:4000], 0644)
+	os.WriteFile(destination+partialSourceSuffix, []byte(server.URL), 0644)
+
+	if err := fileWithOptions(server.URL, destination, testOptions); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if requestedRange != "bytes=4000-" {
+		t.Errorf("Expected download to resume at byte 4000, requested %s", requestedRange)
+	}
+
+	content, _ := os.ReadFile(destination)
+
+	if !bytes.Equal(content, mockContent) {
+		t.Errorf("Downloaded content does not match.")
+	}
+}
+
+func TestFileDoesNotResumePartialDownloadFromAnotherSource(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	requestedRange := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedRange = r.Header.Get("Range")
+		serveMockContent(w, r)
+	}))
+	defer server.Close()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+	os.WriteFile(destination+partialSuffix, []byte("other"), 0644)
+	os.WriteFile(destination+partialSourceSuffix, []byte("https://mirror.example.com/Python-1.2.3.tgz"), 0644)
+
+	if err := fileWithOptions(server.URL, destination, testOptions); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if requestedRange != "" {
+		t.Errorf("Did not expect the download to resume, requested %s", requestedRange)
+	}
+
+	if content, _ := os.ReadFile(destination); !bytes.Equal(content, mockContent) {
+		t.Errorf("Downloaded content does not match.")
+	}
+
+	if _, err := os.Stat(destination + partialSourceSuffix); !os.IsNotExist(err) {
+		t.Errorf("Expected the partial source record to be removed.")
+	}
+}
+
+func TestFileAcceptsCompletePartialDownload(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	server := httptest.NewServer(http.HandlerFunc(serveMockContent))
+	defer server.Close()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+	os.WriteFile(destination+partialSuffix, mockContent, 0644)
+	os.WriteFile(destination+partialSourceSuffix, []byte(server.URL), 0644)
+
+	if err := fileWithOptions(server.URL, destination, testOptions); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if content, _ := os.ReadFile(destination); !bytes.Equal(content, mockContent) {
+		t.Errorf("Downloaded content does not match.")
+	}
+}
+
+func TestFileRestartsOversizedPartialDownload(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	server := httptest.NewServer(http.HandlerFunc(serveMockContent))
+	defer server.Close()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+	os.WriteFile(destination+partialSuffix, append(bytes.Clone(mockContent), "garbage"...), 0644)
+	os.WriteFile(destination+partialSourceSuffix, []byte(server.URL), 0644)
+
+	if err := fileWithOptions(server.URL, destination, testOptions); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if content, _ := os.ReadFile(destination); !bytes.Equal(content, mockContent) {
+		t.Errorf("Expected the oversized partial file to be downloaded again, got %d bytes", len(content))
+	}
+}
+
+func TestFileRetriesOnServerError(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		serveMockContent(w, r)
+	}))
+	defer server.Close()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+
+	if err := fileWithOptions(server.URL, destination, testOptions); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestFileDoesNotRetryMissingFile(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+	err := fileWithOptions(server.URL, destination, testOptions)
+
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected 404 error, got %v", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected a single attempt, got %d", attempts)
+	}
+
+	if _, err := os.Stat(destination); !os.IsNotExist(err) {
+		t.Errorf("Did not expect destination to be created.")
+	}
+}
+
+func TestReadOptionsUsesConfiguration(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"download": {"connectTimeout": "5s", "retries": 0}}`), 0750)
+
+	options, err := ReadOptions()
+
+	if err != nil || options.ConnectTimeout != 5*time.Second || options.Retries != 0 || options.StallTimeout != defaultOptions.StallTimeout {
+		t.Errorf("Unexpected options: %v (%v)", options, err)
+	}
+}
+
+func TestProgressReporterFormatsStatus(t *testing.T) {
+	reporter := newProgressReporter(1024, 4096)
+	reporter.written = 1024
+
+	status := reporter.formatStatus()
+
+	if !strings.HasPrefix(status, "2.0 KB / 4.0 KB (50%)") {
+		t.Errorf("Unexpected status: %s", status)
+	}
+}
+
+func TestFileCopiesLocalSources(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	source := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+	os.WriteFile(source, mockContent, 0644)
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+
+	if err := fileWithOptions("file://"+source, destination, Options{Offline: true}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	content, _ := os.ReadFile(destination)
+
+	if !bytes.Equal(content, mockContent) {
+		t.Errorf("Copied content does not match.")
+	}
+}
+
+func TestFileRefusesNetworkAccessOffline(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	destination := path.Join(t.TempDir(), "Python-1.2.3.tgz")
+
+	if err := fileWithOptions("https://www.python.org/ftp/python", destination, Options{Offline: true}); err != ErrOffline {
+		t.Errorf("Expected offline error, got %v", err)
+	}
+}
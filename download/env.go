@@ -0,0 +1,87 @@
+package download
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SubprocessEnv returns the environment variables passing the download
+// settings to tools making network calls of their own (git and pip), so that
+// they use the same proxy, certificate authorities and client certificate.
+//
+// Unlike v's own client, git and pip use the configured certificate
+// authorities instead of the system ones rather than on top of them. Headers
+// are only passed to git, and pip expects the client certificate and its key
+// in a single file.
+func SubprocessEnv() ([]string, error) {
+	options, err := ReadOptions()
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	return subprocessEnv(options), nil
+}
+
+func subprocessEnv(options Options) []string {
+	env := []string{}
+
+	if options.Proxy != "" {
+		noProxy := strings.Join(options.NoProxy, ",")
+
+		for _, key := range []string{"HTTPS_PROXY", "HTTP_PROXY", "https_proxy", "http_proxy"} {
+			env = append(env, key+"="+options.Proxy)
+		}
+
+		env = append(env, "NO_PROXY="+noProxy, "no_proxy="+noProxy)
+	}
+
+	if options.CAFile != "" {
+		env = append(env, "GIT_SSL_CAINFO="+options.CAFile, "PIP_CERT="+options.CAFile, "REQUESTS_CA_BUNDLE="+options.CAFile)
+	}
+
+	if options.ClientCert != "" {
+		env = append(env, "GIT_SSL_CERT="+options.ClientCert)
+
+		if options.ClientKey != "" {
+			env = append(env, "GIT_SSL_KEY="+options.ClientKey)
+		}
+
+		if options.ClientKey == "" || options.ClientKey == options.ClientCert {
+			env = append(env, "PIP_CLIENT_CERT="+options.ClientCert)
+		}
+	}
+
+	// Headers are passed as http.<url>.extraHeader git settings.
+	gitConfigCount := 0
+
+	for _, host := range sortedKeys(options.Headers) {
+		for _, name := range sortedKeys(options.Headers[host]) {
+			index := strconv.Itoa(gitConfigCount)
+			env = append(env,
+				"GIT_CONFIG_KEY_"+index+"=http.https://"+host+"/.extraHeader",
+				"GIT_CONFIG_VALUE_"+index+"="+name+": "+options.Headers[host][name],
+			)
+			gitConfigCount++
+		}
+	}
+
+	if gitConfigCount != 0 {
+		env = append(env, "GIT_CONFIG_COUNT="+strconv.Itoa(gitConfigCount))
+	}
+
+	return env
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := []string{}
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,47 @@
+package download
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSubprocessEnvPassesSettings(t *testing.T) {
+	env := subprocessEnv(Options{
+		Proxy:      "http://proxy:3128",
+		NoProxy:    []string{"localhost", ".internal"},
+		CAFile:     "/etc/ca.pem",
+		ClientCert: "/etc/client.pem",
+		Headers:    map[string]map[string]string{"git.example.com": {"Authorization": "Bearer token"}},
+	})
+
+	for _, expected := range []string{
+		"HTTPS_PROXY=http://proxy:3128",
+		"NO_PROXY=localhost,.internal",
+		"GIT_SSL_CAINFO=/etc/ca.pem",
+		"PIP_CERT=/etc/ca.pem",
+		"GIT_SSL_CERT=/etc/client.pem",
+		"PIP_CLIENT_CERT=/etc/client.pem",
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.https://git.example.com/.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Bearer token",
+	} {
+		if !slices.Contains(env, expected) {
+			t.Errorf("Expected %s in %v", expected, env)
+		}
+	}
+}
+
+func TestSubprocessEnvIsEmptyWithoutSettings(t *testing.T) {
+	if env := subprocessEnv(defaultOptions); len(env) != 0 {
+		t.Errorf("Expected no variables, got %v", env)
+	}
+}
+
+func TestSubprocessEnvOnlyPassesCombinedClientCertToPip(t *testing.T) {
+	env := subprocessEnv(Options{ClientCert: "/etc/client.pem", ClientKey: "/etc/client.key"})
+
+	if !slices.Contains(env, "GIT_SSL_KEY=/etc/client.key") || slices.ContainsFunc(env, func(entry string) bool { return strings.HasPrefix(entry, "PIP_CLIENT_CERT=") }) {
+		t.Errorf("Unexpected variables: %v", env)
+	}
+}
@@ -0,0 +1,82 @@
+package download
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// Key of the netrc entry used for hosts without a dedicated entry.
+const netrcDefaultMachine = "default"
+
+type netrcEntry struct {
+	Login    string
+	Password string
+}
+
+// Returns the location of the netrc file, either $NETRC or ~/.netrc.
+func getNetrcPath() string {
+	if netrcPath, found := os.LookupEnv("NETRC"); found {
+		return netrcPath
+	}
+
+	home, _ := os.UserHomeDir()
+
+	return path.Join(home, ".netrc")
+}
+
+// Reads the netrc file. A missing file yields no credentials.
+func readNetrc() (map[string]netrcEntry, error) {
+	content, err := os.ReadFile(getNetrcPath())
+
+	if os.IsNotExist(err) {
+		return map[string]netrcEntry{}, nil
+	}
+
+	if err != nil {
+		return map[string]netrcEntry{}, err
+	}
+
+	return parseNetrc(string(content)), nil
+}
+
+// Parses netrc content into credentials keyed by machine name. The "default"
+// entry, if any, is keyed by netrcDefaultMachine. Macro definitions are not supported.
+func parseNetrc(content string) map[string]netrcEntry {
+	entries := map[string]netrcEntry{}
+	tokens := strings.Fields(content)
+	machine := ""
+
+	for index := 0; index < len(tokens); index++ {
+		token := tokens[index]
+
+		switch token {
+		case "machine":
+			if index+1 < len(tokens) {
+				index++
+				machine = tokens[index]
+				entries[machine] = netrcEntry{}
+			}
+		case "default":
+			machine = netrcDefaultMachine
+			entries[machine] = netrcEntry{}
+		case "login", "password":
+			if machine == "" || index+1 >= len(tokens) {
+				continue
+			}
+
+			index++
+			entry := entries[machine]
+
+			if token == "login" {
+				entry.Login = tokens[index]
+			} else {
+				entry.Password = tokens[index]
+			}
+
+			entries[machine] = entry
+		}
+	}
+
+	return entries
+}
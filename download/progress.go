@@ -0,0 +1,121 @@
+package download
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	logger "v/logger"
+)
+
+const progressBarWidth = 30
+
+// Minimum delay between progress updates, on a terminal and otherwise.
+var (
+	terminalUpdateInterval = 200 * time.Millisecond
+	plainUpdateInterval    = 5 * time.Second
+)
+
+// Reports the progress of a download as data is written to it. On a terminal,
+// a progress bar is redrawn in place; otherwise plain lines are printed
+// periodically so that logs stay readable.
+type progressReporter struct {
+	start      time.Time
+	lastUpdate time.Time
+	initial    int64
+	written    int64
+	total      int64
+	isTerminal bool
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Creates a reporter for a download resuming at <initial> bytes, of <total>
+// bytes (-1 if unknown).
+func newProgressReporter(initial int64, total int64) *progressReporter {
+	return &progressReporter{
+		start:      time.Now(),
+		initial:    initial,
+		total:      total,
+		isTerminal: logger.InfoLogger.Writer() == os.Stdout && isTerminal(os.Stdout),
+	}
+}
+
+func (p *progressReporter) Write(data []byte) (int, error) {
+	p.written += int64(len(data))
+
+	interval := plainUpdateInterval
+
+	if p.isTerminal {
+		interval = terminalUpdateInterval
+	}
+
+	if time.Since(p.lastUpdate) >= interval {
+		p.lastUpdate = time.Now()
+		p.print()
+	}
+
+	return len(data), nil
+}
+
+// Returns the download rate in bytes per second since the reporter started.
+func (p *progressReporter) rate() float64 {
+	elapsed := time.Since(p.start).Seconds()
+
+	if elapsed == 0 {
+		return 0
+	}
+
+	return float64(p.written) / elapsed
+}
+
+func (p *progressReporter) formatStatus() string {
+	current := p.initial + p.written
+	rate := p.rate()
+	status := logger.FormatSize(current)
+
+	if p.total > 0 {
+		status += fmt.Sprintf(" / %s (%d%%)", logger.FormatSize(p.total), current*100/p.total)
+	}
+
+	status += fmt.Sprintf(", %s/s", logger.FormatSize(int64(rate)))
+
+	if p.total > 0 && rate > 0 {
+		eta := time.Duration(float64(p.total-current)/rate) * time.Second
+		status += ", ETA " + eta.Round(time.Second).String()
+	}
+
+	return status
+}
+
+func (p *progressReporter) formatBar() string {
+	if p.total <= 0 {
+		return ""
+	}
+
+	filled := int((p.initial + p.written) * progressBarWidth / p.total)
+
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled) + "] "
+}
+
+func (p *progressReporter) print() {
+	if p.isTerminal {
+		fmt.Fprintf(logger.InfoLogger.Writer(), "\r\033[K%s%s%s", logger.InfoLogger.Prefix(), p.formatBar(), p.formatStatus())
+		return
+	}
+
+	logger.InfoLogger.Println(p.formatStatus())
+}
+
+// Prints the final state of the download.
+func (p *progressReporter) Finish() {
+	p.print()
+
+	if p.isTerminal {
+		fmt.Fprintln(logger.InfoLogger.Writer())
+	}
+}
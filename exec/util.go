@@ -2,24 +2,50 @@ package exec
 
 import (
 	"io"
+	"os"
 	"os/exec"
 	"strings"
 	logger "v/logger"
 )
 
+// Options tweaking how commands are run by RunCommandWithOptions.
+type CommandOptions struct {
+	// Additional environment variables, of the form KEY=VALUE, layered over
+	// the current process' environment.
+	Env []string
+	// Writer receiving the command's stdout and stderr, on top of the debug logger.
+	Log io.Writer
+}
+
 // RunCommand is a thin wrapper around running command-line calls
 // programmatically. It abstracts common configuration like routing
 // output and handling the directory the calls are made from.
 func RunCommand(command []string, cwd string) (string, error) {
+	return RunCommandWithOptions(command, cwd, CommandOptions{})
+}
+
+// RunCommandWithOptions behaves like RunCommand, with additional
+// configuration (see: CommandOptions).
+func RunCommandWithOptions(command []string, cwd string, options CommandOptions) (string, error) {
 	cmd := exec.Command(command[0], command[1:]...)
 
 	cmd.Dir = cwd
 
+	if len(options.Env) != 0 {
+		cmd.Env = append(os.Environ(), options.Env...)
+	}
+
 	var out strings.Builder
 	var errOut strings.Builder
 
-	stdOutMultiWriter := io.MultiWriter(&out, logger.DebugLogger.Writer())
-	stdErrMultiWriter := io.MultiWriter(&errOut, logger.DebugLogger.Writer())
+	log := options.Log
+
+	if log == nil {
+		log = io.Discard
+	}
+
+	stdOutMultiWriter := io.MultiWriter(&out, logger.DebugLogger.Writer(), log)
+	stdErrMultiWriter := io.MultiWriter(&errOut, logger.DebugLogger.Writer(), log)
 
 	cmd.Stdout = stdOutMultiWriter
 	cmd.Stderr = stdErrMultiWriter
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatSize returns a human-readable representation of <size> bytes.
+func FormatSize(size int64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	value := float64(size)
+	unitIndex := 0
+
+	for value >= 1024 && unitIndex < len(units)-1 {
+		value /= 1024
+		unitIndex++
+	}
+
+	if unitIndex == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[unitIndex])
+}
+
+// FormatAge returns a human-readable representation of the time elapsed
+// since <since>.
+func FormatAge(since time.Time) string {
+	elapsed := time.Since(since)
+
+	switch {
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%d minutes ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%d hours ago", int(elapsed.Hours()))
+	default:
+		return fmt.Sprintf("%d days ago", int(elapsed.Hours()/24))
+	}
+}
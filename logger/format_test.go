@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"testing"
+)
+
+func TestFormatSize(t *testing.T) {
+	cases := map[int64]string{
+		512:                    "512 B",
+		25 * 1024 * 1024:       "25.0 MB",
+		3 * 1024 * 1024 * 1024: "3.0 GB",
+	}
+
+	for input, expected := range cases {
+		if formatted := FormatSize(input); formatted != expected {
+			t.Errorf("Expected %s, got %s", expected, formatted)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package python
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	cli "v/cli"
+	logger "v/logger"
+	state "v/state"
+)
+
+// ResolveAlias follows the chain of aliases starting at <version> and returns
+// the version it ends on. Names that are not aliases resolve to themselves.
+// An error is returned if the chain loops back on itself.
+func ResolveAlias(version string, aliases map[string]string) (string, error) {
+	chain := []string{version}
+
+	for {
+		target, found := aliases[version]
+
+		if !found {
+			return version, nil
+		}
+
+		if slices.Contains(chain, target) {
+			return "", errors.New("Alias cycle: " + strings.Join(append(chain, target), " -> "))
+		}
+
+		chain = append(chain, target)
+		version = target
+	}
+}
+
+// Returns the aliases resolving to <version>, in name order.
+func getAliasesOf(version string, aliases map[string]string) []string {
+	names := []string{}
+
+	for name := range aliases {
+		if resolved, err := ResolveAlias(name, aliases); err == nil && resolved == version {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Alias (called via `v python alias <name> <version>`) creates or re-points
+// an alias, which can be used wherever a version is expected.
+func alias(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 3 {
+		return errors.New("Usage: v python alias <name> <version>")
+	}
+
+	name, target := positional[1], positional[2]
+
+	if err := ValidateInstallName(name); err != nil {
+		return err
+	}
+
+	if ValidateReleaseVersion(name) == nil || slices.Contains(GetAvailableVersions(), name) {
+		return errors.New("Cannot use " + name + " as an alias: it is a version name.")
+	}
+
+	aliases := maps.Clone(currentState.Aliases)
+
+	if aliases == nil {
+		aliases = map[string]string{}
+	}
+
+	aliases[name] = target
+
+	resolved, err := ResolveAlias(name, aliases)
+
+	if err != nil {
+		return err
+	}
+
+	currentState.Aliases = aliases
+
+	if err := state.SaveState(currentState); err != nil {
+		return err
+	}
+
+	if !slices.Contains(GetAvailableVersions(), resolved) {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: Python " + resolved + " is not installed."))
+	}
+
+	logger.InfoLogger.Printf("%s now points to %s\n", logger.Bold(name), target)
+	return nil
+}
+
+// Unalias (called via `v python unalias <name>`) removes an alias. Aliases
+// other aliases point to cannot be removed.
+func unalias(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 2 {
+		return errors.New("Missing alias to remove.")
+	}
+
+	name := positional[1]
+
+	if _, found := currentState.Aliases[name]; !found {
+		return errors.New("No alias named " + name + ".")
+	}
+
+	for other, target := range currentState.Aliases {
+		if target == name {
+			return errors.New("Alias " + other + " points to " + name + ". Remove or re-point it first.")
+		}
+	}
+
+	if currentState.GlobalVersion == name {
+		return errors.New("Alias " + name + " is the global version. Select another one first, with: v python use <version>")
+	}
+
+	delete(currentState.Aliases, name)
+
+	if err := state.SaveState(currentState); err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Removed alias %s\n", name)
+	return nil
+}
+
+// Aliases (called via `v python aliases`) lists the aliases and the
+// versions they resolve to.
+func listAliases(args []string, flags cli.Flags, currentState state.State) error {
+	if len(currentState.Aliases) == 0 {
+		logger.InfoLogger.Println("No aliases defined!")
+		return nil
+	}
+
+	names := []string{}
+
+	for name := range currentState.Aliases {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		target := currentState.Aliases[name]
+		line := name + " -> " + target
+
+		if resolved, err := ResolveAlias(name, currentState.Aliases); err != nil {
+			line += logger.Yellow(" (" + err.Error() + ")")
+		} else if resolved != target {
+			line += " (" + resolved + ")"
+		}
+
+		logger.InfoLogger.Println(line)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestResolveAliasFollowsChains(t *testing.T) {
+	aliases := map[string]string{"default": "work", "work": "3.11.4"}
+
+	for name, expected := range map[string]string{"default": "3.11.4", "work": "3.11.4", "3.10.0": "3.10.0"} {
+		if resolved, err := ResolveAlias(name, aliases); err != nil || resolved != expected {
+			t.Errorf("Expected %s to resolve to %s, got %s (%v)", name, expected, resolved, err)
+		}
+	}
+}
+
+func TestResolveAliasDetectsCycles(t *testing.T) {
+	aliases := map[string]string{"a": "b", "b": "c", "c": "a"}
+
+	_, err := ResolveAlias("a", aliases)
+
+	if err == nil || !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("Expected cycle error, got %v", err)
+	}
+}
+
+func TestAliasRejectsCycles(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	currentState := state.State{Aliases: map[string]string{"work": "default"}}
+
+	if err := alias([]string{"alias", "default", "work"}, cli.Flags{}, currentState); err == nil {
+		t.Errorf("Expected an error for an alias cycle.")
+	}
+
+	if len(state.ReadState().Aliases) != 0 {
+		t.Errorf("Expected the state to be left untouched.")
+	}
+}
+
+func TestAliasRejectsVersionNames(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	if err := alias([]string{"alias", "3.11.4", "3.10.0"}, cli.Flags{}, state.State{}); err == nil {
+		t.Errorf("Expected an error for an alias named like a version.")
+	}
+}
+
+func TestUnaliasRefusesAliasesInUse(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	currentState := state.State{Aliases: map[string]string{"default": "work", "work": "3.11.4"}}
+
+	if err := unalias([]string{"unalias", "work"}, cli.Flags{}, currentState); err == nil || !strings.Contains(err.Error(), "default") {
+		t.Errorf("Expected an error mentioning default, got %v", err)
+	}
+
+	if err := unalias([]string{"unalias", "default"}, cli.Flags{}, currentState); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, found := state.ReadState().Aliases["default"]; found {
+		t.Errorf("Expected default to be removed.")
+	}
+}
+
+func TestUnaliasRefusesGlobalVersion(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	currentState := state.State{GlobalVersion: "work", Aliases: map[string]string{"work": "3.11.4"}}
+
+	if err := unalias([]string{"unalias", "work"}, cli.Flags{}, currentState); err == nil || !strings.Contains(err.Error(), "global version") {
+		t.Errorf("Expected an error about the global version, got %v", err)
+	}
+
+	if _, found := currentState.Aliases["work"]; !found {
+		t.Errorf("Expected work to be kept.")
+	}
+}
+
+func TestDetermineSelectedPythonVersionResolvesAliasInPythonVersionFile(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	temporaryWd := t.TempDir()
+	os.Chdir(temporaryWd)
+	os.WriteFile(path.Join(temporaryWd, ".python-version"), []byte("default"), 0750)
+
+	version, err := DetermineSelectedPythonVersion(state.State{Aliases: map[string]string{"default": "work", "work": "3.11.4"}})
+
+	if err != nil || version.Version != "3.11.4" || version.Alias != "default" {
+		t.Errorf("Expected 3.11.4 through default, got %v (%v)", version, err)
+	}
+}
+
+func TestListVersionsShowsAliases(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+	WriteManifest("1.2.3", Manifest{Version: "1.2.3"})
+	out := testutils.CaptureOutput(t)
+
+	listVersions([]string{}, cli.Flags{}, state.State{Aliases: map[string]string{"work": "1.2.3", "default": "work"}})
+
+	if captured := out.String(); captured != "1.2.3 (default, work)\n" {
+		t.Errorf("Unexpected message: %s", captured)
+	}
+}
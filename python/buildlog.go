@@ -0,0 +1,106 @@
+package python
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	state "v/state"
+)
+
+const logTimestampFormat = "20060102-150405"
+
+// Number of log lines printed when an install fails.
+const failureLogTailLength = 20
+
+// Log file capturing the output of every stage of an install.
+type buildLog struct {
+	Path string
+	file *os.File
+}
+
+func getLogsPath(version string, segments ...string) string {
+	return state.GetStatePath(append([]string{"logs", "python", version}, segments...)...)
+}
+
+// Creates a new timestamped log file for an install of <version>.
+func newBuildLog(version string) (*buildLog, error) {
+	if err := os.MkdirAll(getLogsPath(version), 0775); err != nil {
+		return nil, err
+	}
+
+	logPath := getLogsPath(version, time.Now().Format(logTimestampFormat)+".log")
+	file, err := os.Create(logPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &buildLog{Path: logPath, file: file}, nil
+}
+
+func (l *buildLog) Write(p []byte) (int, error) {
+	return l.file.Write(p)
+}
+
+// Marks the start of a new stage in the log.
+func (l *buildLog) Stage(label string) {
+	fmt.Fprintf(l.file, "\n==> %s (%s)\n", label, time.Now().Format(time.RFC3339))
+}
+
+func (l *buildLog) Close() error {
+	return l.file.Close()
+}
+
+// Returns the path to the most recent log file for <version>.
+func findLatestBuildLog(version string) (string, error) {
+	entries, err := os.ReadDir(getLogsPath(version))
+
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	logFiles := []string{}
+
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".log") {
+			logFiles = append(logFiles, entry.Name())
+		}
+	}
+
+	if len(logFiles) == 0 {
+		return "", errors.New("No logs found for Python " + version + ".")
+	}
+
+	slices.Sort(logFiles)
+
+	return getLogsPath(version, logFiles[len(logFiles)-1]), nil
+}
+
+// Returns up to the last <count> lines of the file at <filePath>.
+func tailFile(filePath string, count int) ([]string, error) {
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	defer file.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+
+		if len(lines) > count {
+			lines = lines[1:]
+		}
+	}
+
+	return lines, scanner.Err()
+}
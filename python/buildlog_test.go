@@ -0,0 +1,75 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	testutils "v/testutils"
+)
+
+func TestNewBuildLogCreatesLogUnderStatePath(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	log, err := newBuildLog("1.2.3")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	log.Stage("Configure")
+	log.Write([]byte("checking for gcc... gcc\n"))
+	log.Close()
+
+	if path.Dir(log.Path) != getLogsPath("1.2.3") {
+		t.Errorf("Unexpected log location: %s", log.Path)
+	}
+
+	content, _ := os.ReadFile(log.Path)
+
+	if !strings.Contains(string(content), "==> Configure") || !strings.Contains(string(content), "checking for gcc") {
+		t.Errorf("Unexpected log content: %s", content)
+	}
+}
+
+func TestFindLatestBuildLogReturnsMostRecent(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(getLogsPath("1.2.3"), 0750)
+	for _, name := range []string{"20260101-100000.log", "20261018-100000.log", "20250101-100000.log"} {
+		os.WriteFile(getLogsPath("1.2.3", name), []byte(""), 0750)
+	}
+
+	latest, err := findLatestBuildLog("1.2.3")
+
+	if err != nil || latest != getLogsPath("1.2.3", "20261018-100000.log") {
+		t.Errorf("Unexpected latest log: %s (%v)", latest, err)
+	}
+}
+
+func TestFindLatestBuildLogReturnsErrorIfNoLogs(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	if _, err := findLatestBuildLog("1.2.3"); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestTailFileReturnsLastLines(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	filePath := path.Join(t.TempDir(), "test.log")
+	lines := []string{}
+	for index := 0; index < 30; index++ {
+		lines = append(lines, fmt.Sprintf("line %d", index))
+	}
+	os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0750)
+
+	tail, err := tailFile(filePath, 5)
+
+	if err != nil || !slices.Equal(tail, lines[25:]) {
+		t.Errorf("Unexpected tail: %v (%v)", tail, err)
+	}
+}
@@ -7,17 +7,45 @@ import (
 func GetNamespace() cli.Namespace {
 	pythonCommands := cli.Namespace{Label: "python"}
 	pythonCommands.AddCommand(
-		"install", installPython, "v python install <version>", "Downloads, builds and installs a new version of Python.",
+		"install", installPython, "v python install <version> [--git <ref> | --source-dir <path>] [--offline] [--from-archive <path>] [--patch <file>] [--skip-default-packages] [--jobs <n>] [--lto] [--no-pgo] [--configure-opt <arg>] [--build-env KEY=VALUE]", "Downloads, builds and installs a new version of Python.",
 	).AddCommand(
-		"uninstall", uninstallPython, "v python uninstall <version>", "Uninstalls the given Python version.",
+		"upgrade", upgrade, "v python upgrade [version] [--update-version-file] [--uninstall-old]", "Moves to the latest patch release of a version, migrating its packages.",
+	).AddCommand(
+		"uninstall", uninstallPython, "v python uninstall <version>... | --all | --all-except <versions> [--yes] [--force]", "Uninstalls the given Python versions.",
+	).AddCommand(
+		"gc", gc, "v python gc --unused-for <duration> [--dry-run] [--yes]", "Uninstalls versions unused for the given duration and referenced by nothing.",
 	).AddCommand(
 		"use", use, "v python use <version>", "Selects which Python version to use.",
 	).AddCommand(
 		"ls", listVersions, "v python ls", "Lists the installed Python versions.",
+	).AddCommand(
+		"ls-remote", listRemoteVersions, "v python ls-remote [version prefix]", "Lists the Python versions available for download.",
+	).AddCommand(
+		"outdated", outdated, "v python outdated [--raw]", "Reports installed versions with newer patch releases or past their end of life.",
 	).AddCommand(
 		"version", currentVersion, "v python version", "Prints the current version and its source.",
+	).AddCommand(
+		"exec", execCommand, "v python exec <version> [--install] -- <command> [args]", "Runs a command with the given version first on PATH, without selecting it.",
 	).AddCommand(
 		"which", which, "v python which", "Prints the path to the current Python version.",
+	).AddCommand(
+		"info", info, "v python info <version>", "Prints details about how the given version was installed.",
+	).AddCommand(
+		"ls-system", listSystemInterpreters, "v python ls-system", "Lists the interpreters found on PATH and in common prefixes.",
+	).AddCommand(
+		"link", link, "v python link <name> <path-to-prefix-or-interpreter>", "Registers an interpreter installed outside of v as a version.",
+	).AddCommand(
+		"venv", venv, "v python venv <create|ls|rm|which> [name] [--python <version>]", "Manages virtual environments.",
+	).AddCommand(
+		"tool", tool, "v python tool <install|ls|upgrade|reinstall|uninstall> [package] [--python <version>]", "Manages command-line tools installed in their own environment.",
+	).AddCommand(
+		"alias", alias, "v python alias <name> <version>", "Creates or re-points an alias usable in place of a version.",
+	).AddCommand(
+		"unalias", unalias, "v python unalias <name>", "Removes an alias.",
+	).AddCommand(
+		"aliases", listAliases, "v python aliases", "Lists aliases and the versions they point to.",
+	).AddCommand(
+		"logs", logs, "v python logs <version>", "Prints the log of the latest install of the given version.",
 	)
 
 	return pythonCommands
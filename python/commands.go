@@ -1,48 +1,251 @@
 package python
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
+	"path"
+	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
 	cli "v/cli"
 	logger "v/logger"
 	state "v/state"
 )
 
+// Returns the installed versions in use, along with the reason: the global
+// version and the version selected in the current directory, aliases resolved.
+func getVersionsInUse(currentState state.State) map[string]string {
+	inUse := map[string]string{}
+
+	if projectVersion, found := SearchForProjectSelection(); found {
+		if resolved, err := ResolveAlias(projectVersion.Version, currentState.Aliases); err == nil {
+			inUse[resolved] = "selected by " + projectVersion.Source
+		}
+	}
+
+	if currentState.GlobalVersion != "" {
+		if resolved, err := ResolveAlias(currentState.GlobalVersion, currentState.Aliases); err == nil {
+			inUse[resolved] = "the global version"
+		}
+	}
+
+	return inUse
+}
+
+// Returns the versions to uninstall: those named in <names>, or all installed
+// versions but those excluded via --all-except with --all or --all-except.
+func selectVersionsToUninstall(names []string, flags cli.Flags, installedVersions []string) ([]string, error) {
+	if flags.All {
+		if len(names) != 0 {
+			return []string{}, errors.New("Pass either versions to uninstall or --all/--all-except, not both.")
+		}
+
+		// A mistyped exception would otherwise uninstall the version meant to be kept.
+		for _, kept := range flags.AllExcept {
+			if !slices.Contains(installedVersions, kept) {
+				return []string{}, errors.New("Python " + kept + " is not installed. Check the versions passed to --all-except.")
+			}
+		}
+
+		selected := []string{}
+
+		for _, version := range installedVersions {
+			if !slices.Contains(flags.AllExcept, version) {
+				selected = append(selected, version)
+			}
+		}
+
+		return selected, nil
+	}
+
+	if len(names) == 0 {
+		return []string{}, errors.New("Missing version to uninstall.")
+	}
+
+	selected := []string{}
+
+	for _, name := range names {
+		if err := ValidateInstallName(name); err != nil {
+			return []string{}, err
+		}
+
+		if !slices.Contains(installedVersions, name) {
+			return []string{}, errors.New("Python " + name + " is not installed.")
+		}
+
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, nil
+}
+
+// Removes the installed version <version>, running the uninstall hooks.
+func uninstallVersion(version string) error {
+	if venvs := getVenvsUsing(version); len(venvs) != 0 {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: The following virtual environments use Python " + version + " and will stop working: " + strings.Join(venvs, ", ")))
+	}
+
+	if tools := getToolsUsing(version); len(tools) != 0 {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: The following tools use Python " + version + " and will stop working until reinstalled (see: v python tool reinstall): " + strings.Join(tools, ", ")))
+	}
+
+	if err := runHook("pre-uninstall", version); err != nil {
+		return err
+	}
+
+	runtimePath := state.GetStatePath("runtimes", "python", version)
+
+	if err := os.RemoveAll(runtimePath); err != nil {
+		return err
+	}
+
+	// A later reinstall must not inherit the last use of this install.
+	if err := os.Remove(getLastUsedPath(version)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Uninstalled Python %s\n", version)
+	runPostHook("post-uninstall", version)
+
+	return nil
+}
+
+// Uninstall (called via `v python uninstall <version>... | --all | --all-except <versions>`)
+// removes installed versions after confirmation (skipped with --yes). Versions
+// in use (global or selected in the current directory) are refused when named,
+// and skipped with --all, unless --force is passed.
 func uninstallPython(args []string, flags cli.Flags, currentState state.State) error {
-	runtimePath := state.GetStatePath("runtimes", "python", args[1])
-	err := os.RemoveAll(runtimePath)
-	return err
+	installedVersions, err := ListInstalledVersions()
+
+	if err != nil {
+		return err
+	}
+
+	versions, err := selectVersionsToUninstall(cli.Positional(args)[1:], flags, installedVersions)
+
+	if err != nil {
+		return err
+	}
+
+	inUse := getVersionsInUse(currentState)
+	kept := []string{}
+
+	for _, version := range versions {
+		reason, isInUse := inUse[version]
+
+		switch {
+		case !isInUse:
+			kept = append(kept, version)
+		case flags.Force:
+			logger.InfoLogger.Println(logger.Yellow("WARNING: Python " + version + " is " + reason + "."))
+			kept = append(kept, version)
+		case flags.All:
+			logger.InfoLogger.Println("Skipping Python " + version + ": it is " + reason + ". Pass --force to uninstall it anyway.")
+		default:
+			return errors.New("Python " + version + " is " + reason + ". Select another version first, or pass --force to uninstall it anyway.")
+		}
+	}
+
+	if len(kept) == 0 {
+		logger.InfoLogger.Println("Nothing to uninstall.")
+		return nil
+	}
+
+	if !flags.Yes && !cli.Confirm("Uninstall Python "+strings.Join(kept, ", ")+"?") {
+		return errors.New("Uninstall cancelled. Pass --yes to skip the confirmation.")
+	}
+
+	for _, version := range kept {
+		if err := uninstallVersion(version); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func installPython(args []string, flags cli.Flags, currentState state.State) error {
-	version := args[1]
+	positional := cli.Positional(args)
+	version := ""
+
+	if len(positional) > 1 {
+		version = positional[1]
+	} else if flags.GitRef != "" {
+		version = strings.ReplaceAll(flags.GitRef, "/", "-") + "-" + time.Now().Format("20060102")
+	} else if flags.SourceDir != "" {
+		absoluteSourceDir, _ := filepath.Abs(flags.SourceDir)
+		version = path.Base(absoluteSourceDir) + "-" + time.Now().Format("20060102")
+	} else if flags.FromArchive != "" {
+		archiveVersion, err := getArchiveVersion(flags.FromArchive)
+
+		if err != nil {
+			return err
+		}
+
+		version = archiveVersion
+	} else {
+		return errors.New("Missing version to install.")
+	}
+
+	options, err := installOptionsFromFlags(flags)
 
-	return InstallPythonDistribution(version, flags.NoCache)
+	if err != nil {
+		return err
+	}
+
+	return InstallPythonDistribution(version, options)
 }
 
 func use(args []string, flags cli.Flags, currentState state.State) error {
-	version := args[1]
-	if err := ValidateVersion(version); err != nil {
-		return err
+	positional := cli.Positional(args)
+
+	if len(positional) < 2 {
+		return errors.New("Missing version to use.")
 	}
 
-	availableVersions := GetAvailableVersions()
+	// Aliases are stored as-is so that re-pointing them takes effect.
+	selected := positional[1]
+	version, err := ResolveAlias(selected, currentState.Aliases)
 
-	found := false
-	for _, v := range availableVersions {
-		if v == version {
-			found = true
-			break
-		}
+	if err != nil {
+		return err
 	}
 
+	found := slices.Contains(GetAvailableVersions(), version)
+
 	if !found {
+		if err := ValidateReleaseVersion(version); err != nil {
+			return err
+		}
+
 		logger.InfoLogger.Println("Version not installed. Installing it first.")
-		InstallPythonDistribution(version, flags.NoCache)
+
+		options, err := installOptionsFromFlags(flags)
+
+		if err != nil {
+			return err
+		}
+
+		if err := InstallPythonDistribution(version, options); err != nil {
+			return err
+		}
 	}
 
-	state.WriteState(version)
-	logger.InfoLogger.Printf("Now using Python %s\n", version)
+	state.WriteState(selected)
+
+	if selected != version {
+		logger.InfoLogger.Printf("Now using Python %s (alias %s)\n", version, selected)
+	} else {
+		logger.InfoLogger.Printf("Now using Python %s\n", version)
+	}
+
+	// V_SELECTED holds the name passed by the user, which may be an alias.
+	runPostHook("post-use", version, "V_SELECTED="+selected)
 
 	return nil
 }
@@ -60,7 +263,125 @@ func listVersions(args []string, flags cli.Flags, currentState state.State) erro
 	}
 
 	for _, d := range installedVersions {
-		logger.InfoLogger.Println(d)
+		line := d
+
+		if aliases := getAliasesOf(d, currentState.Aliases); len(aliases) != 0 {
+			line += " (" + strings.Join(aliases, ", ") + ")"
+		}
+
+		if manifest, err := ReadManifest(d); err == nil && manifest.Prefix != "" {
+			line += " (linked: " + manifest.Prefix + ")"
+		}
+
+		if lastUsed, found := GetLastUsed(d); found {
+			line += " (last used " + lastUsed.Format(time.DateOnly) + ")"
+		}
+
+		if !IsCompleteInstall(d) {
+			line += logger.Yellow(" (incomplete: no install manifest)")
+		}
+
+		logger.InfoLogger.Println(line)
+	}
+
+	return nil
+}
+
+// Info prints the details recorded in the install manifest of the given version.
+func info(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 2 {
+		return errors.New("Missing version to show details for.")
+	}
+
+	version := positional[1]
+	installedVersions, _ := ListInstalledVersions()
+
+	if !slices.Contains(installedVersions, version) {
+		return errors.New("Python " + version + " is not installed.")
+	}
+
+	manifest, err := ReadManifest(version)
+
+	if os.IsNotExist(err) {
+		return errors.New("Python " + version + " has no install manifest. It may be an incomplete install.")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if flags.RawOutput {
+		d, _ := json.MarshalIndent(manifest, "", "  ")
+		logger.InfoLogger.Println(string(d))
+		return nil
+	}
+
+	details := [][]string{
+		{"Version", manifest.Version},
+		{"Install path", state.GetStatePath("runtimes", "python", version)},
+		{"Linked prefix", manifest.Prefix},
+		{"Imported from", manifest.ImportedFrom},
+		{"Source", manifest.SourceURL},
+		{"Archive digest", manifest.ArchiveDigest},
+		{"Git ref", manifest.GitRef},
+		{"Git commit", manifest.GitCommit},
+		{"Interpreter", GetInterpreterPath(version)},
+		{"Patches", formatAppliedPatches(manifest.Patches)},
+		{"Default packages", formatDefaultPackages(manifest.DefaultPackages)},
+		{"Installed at", manifest.InstalledAt.Local().Format(time.RFC1123)},
+	}
+
+	// Prebuilt installs (i.e. PyPy) have no build details.
+	if manifest.BuildOptions.Jobs != 0 {
+		details = append(details, [][]string{
+			{"Build duration", manifest.BuildDuration.String()},
+			{"Build jobs", strconv.Itoa(manifest.BuildOptions.Jobs)},
+			{"Configure args", strings.Join(manifest.BuildOptions.ConfigureArgs(state.GetStatePath("runtimes", "python", version)), " ")},
+			{"Build environment", strings.Join(manifest.BuildOptions.EnvList(), " ")},
+		}...)
+	}
+
+	details = append(details, [][]string{
+		{"Build log", manifest.LogPath},
+		{"Host", manifest.Host.Hostname + " (" + manifest.Host.OS + "/" + manifest.Host.Arch + ")"},
+		{"Installed by", "v " + manifest.VVersion},
+	}...)
+
+	for _, detail := range details {
+		if detail[1] == "" {
+			continue
+		}
+
+		logger.InfoLogger.Printf("%-20s%s\n", detail[0]+":", detail[1])
+	}
+
+	return nil
+}
+
+// Lists the versions available for download, optionally filtered by a
+// version prefix (i.e. `v python ls-remote 3.11`).
+func listRemoteVersions(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+	offline, err := isOffline(flags)
+
+	if err != nil {
+		return err
+	}
+
+	remoteVersions, err := ListRemoteVersions(offline)
+
+	if err != nil {
+		return err
+	}
+
+	for _, version := range remoteVersions {
+		if len(positional) > 1 && version != positional[1] && !strings.HasPrefix(version, positional[1]+".") {
+			continue
+		}
+
+		logger.InfoLogger.Println(version)
 	}
 
 	return nil
@@ -68,18 +389,32 @@ func listVersions(args []string, flags cli.Flags, currentState state.State) erro
 
 // Which prints out the system path to the executable being used by `python`.
 func which(args []string, flags cli.Flags, currentState state.State) error {
-	selectedVersion, _ := DetermineSelectedPythonVersion(currentState)
+	selectedVersion, err := DetermineSelectedPythonVersion(currentState)
+
+	if err != nil {
+		return err
+	}
+
 	installedVersions, _ := ListInstalledVersions()
 	isInstalled := slices.Contains(installedVersions, selectedVersion.Version)
 
+	// Shims resolve the interpreter through here, which makes it the place to track use.
+	if isInstalled {
+		recordUse(selectedVersion.Version)
+	}
+
 	var printedPath string
 
-	if selectedVersion.Source == "system" {
-		_, sysPath := DetermineSystemPython()
-		printedPath = sysPath + " (system)"
+	if selectedVersion.Venv != "" {
+		printedPath = getVenvInterpreterPath(selectedVersion.Venv)
+	} else if selectedVersion.Source == "system" {
+		if selectedVersion.Path == "" {
+			return errors.New("No version selected and no system Python found.")
+		}
+
+		printedPath = selectedVersion.Path + " (system)"
 	} else if isInstalled {
-		tag := VersionStringToStruct(selectedVersion.Version)
-		printedPath = state.GetStatePath("runtimes", "python", selectedVersion.Version, "bin", "python"+tag.MajorMinor())
+		printedPath = GetInterpreterPath(selectedVersion.Version)
 	} else {
 		logger.InfoLogger.Printf("The desired version (%s) is not installed.\n", selectedVersion.Version)
 		return nil
@@ -99,13 +434,19 @@ func which(args []string, flags cli.Flags, currentState state.State) error {
 
 // CurrentVersion (called via `v version`) outputs the currently selected version
 // and what configures it. If the version is configured by a file, the file is returned
-// under "source", if the system Python is used, "system" is returned as a source.
+// under "source", if the system Python is used, "system" is returned as a source. If a
+// virtual environment is selected, it is printed as well.
 func currentVersion(args []string, flags cli.Flags, currentState state.State) error {
-	selectedVersion, _ := DetermineSelectedPythonVersion(currentState)
+	selectedVersion, err := DetermineSelectedPythonVersion(currentState)
+
+	if err != nil {
+		return err
+	}
+
 	installedVersions, _ := ListInstalledVersions()
 	isInstalled := slices.Contains(installedVersions, selectedVersion.Version)
 
-	if !isInstalled {
+	if !isInstalled && selectedVersion.Venv == "" {
 		logger.InfoLogger.Println(logger.Bold(logger.Yellow("WARNING: This version is not installed.")))
 	}
 
@@ -114,6 +455,77 @@ func currentVersion(args []string, flags cli.Flags, currentState state.State) er
 		return nil
 	}
 
-	logger.InfoLogger.Printf("Python version: %s\nSource: %s\n", logger.Bold(selectedVersion.Version), logger.Bold(selectedVersion.Source))
+	displayedVersion := logger.Bold(selectedVersion.Version)
+
+	if selectedVersion.Alias != "" {
+		displayedVersion += " (alias " + selectedVersion.Alias + ")"
+	}
+
+	logger.InfoLogger.Printf("Python version: %s\nSource: %s\n", displayedVersion, logger.Bold(selectedVersion.Source))
+
+	if selectedVersion.Venv != "" {
+		logger.InfoLogger.Printf("Virtual environment: %s\n", logger.Bold(selectedVersion.Venv))
+	}
+
 	return nil
 }
+
+// Logs prints the most recent install log for the given version.
+func logs(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 2 {
+		return errors.New("Missing version to show logs for.")
+	}
+
+	if err := ValidateInstallName(positional[1]); err != nil {
+		return err
+	}
+
+	logPath, err := findLatestBuildLog(positional[1])
+
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(logPath)
+
+	if err != nil {
+		return err
+	}
+
+	if !flags.RawOutput {
+		logger.InfoLogger.Println(logger.Bold("Log file: " + logPath))
+	}
+
+	logger.InfoLogger.Print(string(content))
+	return nil
+}
+
+func formatAppliedPatches(patches []AppliedPatch) string {
+	names := []string{}
+
+	for _, patch := range patches {
+		names = append(names, patch.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func formatDefaultPackages(result *DefaultPackagesResult) string {
+	if result == nil {
+		return ""
+	}
+
+	formatted := strings.Join(result.Requirements, ", ")
+
+	if result.Skipped != "" {
+		return formatted + " (skipped: " + result.Skipped + ")"
+	}
+
+	if !result.Installed {
+		formatted += " (failed: " + result.Error + ")"
+	}
+
+	return formatted
+}
@@ -1,12 +1,13 @@
 package python
 
 import (
-	"bytes"
+	"io"
 	"os"
+	"path"
+	"slices"
 	"strings"
 	"testing"
 	cli "v/cli"
-	logger "v/logger"
 	state "v/state"
 	testutils "v/testutils"
 )
@@ -15,10 +16,7 @@ func TestListVersionOutputsNoticeIfNoVersionsInstalled(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
 	os.MkdirAll(state.GetStatePath("runtimes", "python"), 0750)
-	var out bytes.Buffer
-
-	logger.InfoLogger.SetOutput(&out)
-	defer logger.InfoLogger.SetOutput(os.Stdout)
+	out := testutils.CaptureOutput(t)
 
 	listVersions([]string{}, cli.Flags{}, state.State{})
 
@@ -32,10 +30,8 @@ func TestListVersionOutputsVersionsInstalled(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
 	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
-	var out bytes.Buffer
-
-	logger.InfoLogger.SetOutput(&out)
-	defer logger.InfoLogger.SetOutput(os.Stdout)
+	WriteManifest("1.2.3", Manifest{Version: "1.2.3"})
+	out := testutils.CaptureOutput(t)
 
 	listVersions([]string{}, cli.Flags{}, state.State{})
 
@@ -45,13 +41,24 @@ func TestListVersionOutputsVersionsInstalled(t *testing.T) {
 	}
 }
 
-func TestListVersionReturnsErrorOnFailure(t *testing.T) {
+func TestListVersionFlagsInstallsWithoutManifest(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
-	var out bytes.Buffer
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+	out := testutils.CaptureOutput(t)
+
+	listVersions([]string{}, cli.Flags{}, state.State{})
+
+	captured := out.String()
+	if !strings.HasPrefix(captured, "1.2.3") || !strings.Contains(captured, "incomplete") {
+		t.Errorf("Unexpected message: %s", captured)
+	}
+}
+
+func TestListVersionReturnsErrorOnFailure(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
 
-	logger.InfoLogger.SetOutput(&out)
-	defer logger.InfoLogger.SetOutput(os.Stdout)
+	out := testutils.CaptureOutput(t)
 
 	err := listVersions([]string{}, cli.Flags{}, state.State{})
 
@@ -68,10 +75,7 @@ func TestListVersionReturnsErrorOnFailure(t *testing.T) {
 func TestListVersionOutputsVersionSelectedAndWarnsNotInstalled(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
-	var out bytes.Buffer
-
-	logger.InfoLogger.SetOutput(&out)
-	defer logger.InfoLogger.SetOutput(os.Stdout)
+	out := testutils.CaptureOutput(t)
 
 	which([]string{}, cli.Flags{}, state.State{GlobalVersion: "1.2.3"})
 
@@ -84,10 +88,7 @@ func TestListVersionOutputsVersionSelectedAndWarnsNotInstalled(t *testing.T) {
 func TestWhichOutputsVersionSelectedIfInstalled(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
-	var out bytes.Buffer
-
-	logger.InfoLogger.SetOutput(&out)
-	defer logger.InfoLogger.SetOutput(os.Stdout)
+	out := testutils.CaptureOutput(t)
 
 	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
 	which([]string{}, cli.Flags{}, state.State{GlobalVersion: "1.2.3"})
@@ -102,27 +103,23 @@ func TestWhichOutputsVersionSelectedIfInstalled(t *testing.T) {
 func TestWhichOutputsSystemVersionIfNoneSelected(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
-	var out bytes.Buffer
-
-	logger.InfoLogger.SetOutput(&out)
-	defer logger.InfoLogger.SetOutput(os.Stdout)
+	systemDirectory := setupSystemInterpreters(t, map[string]string{"python3": "3.12.1"})
+	out := testutils.CaptureOutput(t)
 
 	which([]string{}, cli.Flags{RawOutput: true}, state.State{})
 
 	captured := strings.TrimSpace(out.String())
+	expected := path.Join(systemDirectory, "python3") + " (system)"
 
-	if captured != "/bin/python (system)" {
-		t.Errorf("%s != %s", captured, "/bin/python (system)")
+	if captured != expected {
+		t.Errorf("%s != %s", captured, expected)
 	}
 }
 
 func TestWhichOutputsVersionWithoutPrefixesIfRawOutput(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
-	var out bytes.Buffer
-
-	logger.InfoLogger.SetOutput(&out)
-	defer logger.InfoLogger.SetOutput(os.Stdout)
+	out := testutils.CaptureOutput(t)
 
 	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
 	which([]string{}, cli.Flags{RawOutput: true}, state.State{GlobalVersion: "1.2.3"})
@@ -133,3 +130,173 @@ func TestWhichOutputsVersionWithoutPrefixesIfRawOutput(t *testing.T) {
 		t.Errorf("Unexpected message: %s, not %s", captured, expected)
 	}
 }
+
+func TestLogsOutputsLatestLog(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	os.MkdirAll(getLogsPath("1.2.3"), 0750)
+	os.WriteFile(getLogsPath("1.2.3", "20261018-100000.log"), []byte("make: *** [all] Error 1\n"), 0750)
+
+	err := logs([]string{"logs", "1.2.3"}, cli.Flags{RawOutput: true}, state.State{})
+
+	if err != nil || out.String() != "make: *** [all] Error 1\n" {
+		t.Errorf("Unexpected output: %s (%v)", out.String(), err)
+	}
+}
+
+func TestLogsRejectsPathsOutsideLogs(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	if err := logs([]string{"logs", ".."}, cli.Flags{RawOutput: true}, state.State{}); err == nil || !strings.Contains(err.Error(), "Invalid name") {
+		t.Errorf("Expected the name to be rejected, got %v", err)
+	}
+}
+
+func TestInfoOutputsManifestDetails(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+	WriteManifest("1.2.3", Manifest{Version: "1.2.3", SourceURL: "https://example.com/Python-1.2.3.tgz", VVersion: "0.0.8"})
+
+	err := info([]string{"info", "1.2.3"}, cli.Flags{}, state.State{})
+
+	captured := out.String()
+	if err != nil || !strings.Contains(captured, "https://example.com/Python-1.2.3.tgz") || !strings.Contains(captured, "v 0.0.8") {
+		t.Errorf("Unexpected output: %s (%v)", captured, err)
+	}
+}
+
+func TestInfoReturnsErrorIfNoManifest(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+
+	if err := info([]string{"info", "1.2.3"}, cli.Flags{}, state.State{}); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestUninstallRemovesMultipleVersions(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3", "1.2.4", "1.2.5"})
+	testutils.CaptureOutput(t)
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3", "1.2.4"}, cli.Flags{Yes: true}, state.State{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if installed, _ := ListInstalledVersions(); !slices.Equal(installed, []string{"1.2.5"}) {
+		t.Errorf("Expected only 1.2.5 to remain, got %v", installed)
+	}
+}
+
+func TestUninstallRemovesRepeatedVersionsOnce(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3", "1.2.4"})
+	writeHook(t, "pre-uninstall", "echo \"$V_VERSION\" >> "+state.GetStatePath("uninstalled"))
+	testutils.CaptureOutput(t)
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3", "1.2.4", "1.2.3"}, cli.Flags{Yes: true}, state.State{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if content, _ := os.ReadFile(state.GetStatePath("uninstalled")); string(content) != "1.2.3\n1.2.4\n" {
+		t.Errorf("Expected each version to be uninstalled once, got %q", content)
+	}
+}
+
+func TestUninstallReturnsErrorIfNotInstalled(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3"})
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3", "1.2.4"}, cli.Flags{Yes: true}, state.State{}); err == nil {
+		t.Errorf("Expected an error for the missing version.")
+	}
+
+	if !IsCompleteInstall("1.2.3") {
+		t.Errorf("Expected nothing to be uninstalled.")
+	}
+}
+
+func TestUninstallRefusesVersionInUseUnlessForced(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3"})
+	currentState := state.State{GlobalVersion: "default", Aliases: map[string]string{"default": "1.2.3"}}
+	testutils.CaptureOutput(t)
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3"}, cli.Flags{Yes: true}, currentState); err == nil {
+		t.Errorf("Expected the global version to be refused.")
+	}
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3"}, cli.Flags{Yes: true, Force: true}, currentState); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if IsCompleteInstall("1.2.3") {
+		t.Errorf("Expected --force to uninstall the global version.")
+	}
+}
+
+func TestUninstallAllSkipsExceptionsAndVersionsInUse(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3", "1.2.4", "1.2.5"})
+	out := testutils.CaptureOutput(t)
+
+	flags := cli.Flags{Yes: true, All: true, AllExcept: []string{"1.2.4"}}
+
+	if err := uninstallPython([]string{"uninstall"}, flags, state.State{GlobalVersion: "1.2.5"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if installed, _ := ListInstalledVersions(); !slices.Equal(installed, []string{"1.2.4", "1.2.5"}) {
+		t.Errorf("Expected 1.2.4 and 1.2.5 to remain, got %v", installed)
+	}
+
+	if !strings.Contains(out.String(), "Skipping Python 1.2.5") {
+		t.Errorf("Expected the global version to be skipped, got %s", out.String())
+	}
+}
+
+func TestUninstallAllRejectsExceptionsNotInstalled(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3", "1.2.4"})
+	testutils.CaptureOutput(t)
+
+	flags := cli.Flags{Yes: true, All: true, AllExcept: []string{"1.2.9"}}
+
+	if err := uninstallPython([]string{"uninstall"}, flags, state.State{}); err == nil || !strings.Contains(err.Error(), "1.2.9") {
+		t.Errorf("Expected an error naming 1.2.9, got %v", err)
+	}
+
+	if installed, _ := ListInstalledVersions(); len(installed) != 2 {
+		t.Errorf("Expected nothing to be uninstalled, got %v", installed)
+	}
+}
+
+func TestUninstallIsCancelledWithoutConfirmation(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3"})
+	testutils.CaptureOutput(t)
+
+	defer func(previous io.Reader) { cli.PromptInput = previous }(cli.PromptInput)
+	cli.PromptInput = strings.NewReader("n\n")
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3"}, cli.Flags{}, state.State{}); err == nil {
+		t.Errorf("Expected the uninstall to be cancelled.")
+	}
+
+	if !IsCompleteInstall("1.2.3") {
+		t.Errorf("Expected 1.2.3 to be kept.")
+	}
+}
@@ -0,0 +1,166 @@
+package python
+
+import (
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	cli "v/cli"
+	download "v/download"
+	state "v/state"
+)
+
+// Python settings, read from the "python" section of the configuration file.
+type Config struct {
+	Build BuildOptions `json:"build"`
+	// Base URLs to download releases from, in order of preference.
+	Mirrors []string `json:"mirrors"`
+	// Base URLs to download PyPy and GraalPy releases from, keyed by
+	// implementation (see: getImplementationMirrors).
+	ImplementationMirrors map[string][]string `json:"implementationMirrors"`
+	// Local directory holding release archives (Python-<version>.tgz), used
+	// in offline mode.
+	ArchiveDir string `json:"archiveDir"`
+	// Repository development builds are fetched from (see: --git).
+	GitRemote string `json:"gitRemote"`
+	// End-of-life dates (YYYY-MM-DD) of minor versions, keyed by minor version
+	// (i.e. "3.8"), overriding the embedded ones (see: `v python outdated`).
+	EndOfLife map[string]string `json:"endOfLife"`
+	// Directories searched for .python-version files and .venv directories
+	// referencing versions, which are kept by `v python gc`.
+	ProjectRoots []string `json:"projectRoots"`
+}
+
+// Options controlling how CPython is configured and compiled.
+type BuildOptions struct {
+	Jobs          int               `json:"jobs"`
+	ConfigureOpts []string          `json:"configureOpts"`
+	LTO           bool              `json:"lto"`
+	DisablePGO    bool              `json:"disablePgo"`
+	Env           map[string]string `json:"env"`
+}
+
+// Options for a single install, combining configuration defaults and
+// flags passed by the user.
+type InstallOptions struct {
+	NoCache bool
+	Build   BuildOptions
+	// Forbids network access, archives are only resolved locally.
+	Offline    bool
+	ArchiveDir string
+	// Local archive to install from instead of downloading one.
+	FromArchive string
+	// Git ref or local checkout to build from instead of a release.
+	GitRef    string
+	GitRemote string
+	SourceDir string
+	// Patches passed via --patch, applied after those found in the patch directories.
+	Patches []string
+	// Skips installing the packages listed in the default packages file.
+	SkipDefaultPackages bool
+}
+
+func ReadConfig() (Config, error) {
+	config := Config{}
+
+	err := state.ReadConfigSection("python", &config)
+
+	return config, err
+}
+
+// Returns the build options resulting from layering the flags passed by the
+// user over the configuration file defaults. Parallelism defaults to the number
+// of available CPUs.
+func resolveBuildOptions(defaults BuildOptions, flags cli.Flags) BuildOptions {
+	options := defaults
+	options.ConfigureOpts = slices.Clone(defaults.ConfigureOpts)
+	options.Env = maps.Clone(defaults.Env)
+
+	if options.Env == nil {
+		options.Env = map[string]string{}
+	}
+
+	if flags.Jobs != 0 {
+		options.Jobs = flags.Jobs
+	}
+
+	if options.Jobs == 0 {
+		options.Jobs = runtime.NumCPU()
+	}
+
+	options.ConfigureOpts = append(options.ConfigureOpts, flags.ConfigureOpts...)
+	options.LTO = options.LTO || flags.LTO
+	options.DisablePGO = options.DisablePGO || flags.NoPGO
+
+	for _, envVar := range flags.BuildEnv {
+		key, value, _ := strings.Cut(envVar, "=")
+		options.Env[key] = value
+	}
+
+	return options
+}
+
+func installOptionsFromFlags(flags cli.Flags) (InstallOptions, error) {
+	config, err := ReadConfig()
+
+	if err != nil {
+		return InstallOptions{}, err
+	}
+
+	offline, err := isOffline(flags)
+
+	if err != nil {
+		return InstallOptions{}, err
+	}
+
+	return InstallOptions{
+		NoCache:             flags.NoCache,
+		Build:               resolveBuildOptions(config.Build, flags),
+		Offline:             offline,
+		ArchiveDir:          config.ArchiveDir,
+		FromArchive:         flags.FromArchive,
+		GitRef:              flags.GitRef,
+		GitRemote:           config.GitRemote,
+		SourceDir:           flags.SourceDir,
+		Patches:             flags.Patches,
+		SkipDefaultPackages: flags.SkipDefaultPackages,
+	}, nil
+}
+
+// Returns whether network access is disabled, either via --offline or
+// the download.offline configuration.
+func isOffline(flags cli.Flags) (bool, error) {
+	if flags.Offline {
+		return true, nil
+	}
+
+	return download.IsOffline()
+}
+
+// Returns the build environment as a sorted list of KEY=VALUE pairs.
+func (o BuildOptions) EnvList() []string {
+	envList := []string{}
+
+	for key, value := range o.Env {
+		envList = append(envList, key+"="+value)
+	}
+
+	slices.Sort(envList)
+
+	return envList
+}
+
+// Returns the arguments passed to ./configure to install under <prefix>.
+func (o BuildOptions) ConfigureArgs(prefix string) []string {
+	args := []string{"--prefix=" + prefix}
+
+	if !o.DisablePGO {
+		args = append(args, "--enable-optimizations")
+	}
+
+	if o.LTO {
+		args = append(args, "--with-lto")
+	}
+
+	return append(args, o.ConfigureOpts...)
+}
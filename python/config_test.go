@@ -0,0 +1,53 @@
+package python
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	cli "v/cli"
+)
+
+func TestResolveBuildOptionsDefaultsJobsToCPUCount(t *testing.T) {
+	options := resolveBuildOptions(BuildOptions{}, cli.Flags{})
+
+	if options.Jobs != runtime.NumCPU() {
+		t.Errorf("Expected %d jobs, got %d.", runtime.NumCPU(), options.Jobs)
+	}
+}
+
+func TestResolveBuildOptionsLayersFlagsOverDefaults(t *testing.T) {
+	defaults := BuildOptions{Jobs: 2, ConfigureOpts: []string{"--with-pydebug"}, Env: map[string]string{"CFLAGS": "-O1", "LDFLAGS": "-L/opt"}}
+	flags := cli.Flags{Jobs: 8, ConfigureOpts: []string{"--without-doc-strings"}, NoPGO: true, BuildEnv: []string{"CFLAGS=-O2"}}
+
+	options := resolveBuildOptions(defaults, flags)
+
+	if options.Jobs != 8 || !options.DisablePGO {
+		t.Errorf("Unexpected options: %v", options)
+	}
+
+	if !slices.Equal(options.ConfigureOpts, []string{"--with-pydebug", "--without-doc-strings"}) {
+		t.Errorf("Unexpected configure options: %v", options.ConfigureOpts)
+	}
+
+	if !slices.Equal(options.EnvList(), []string{"CFLAGS=-O2", "LDFLAGS=-L/opt"}) {
+		t.Errorf("Unexpected build environment: %v", options.EnvList())
+	}
+
+	if defaults.Env["CFLAGS"] != "-O1" {
+		t.Errorf("Expected defaults to be left untouched.")
+	}
+}
+
+func TestBuildOptionsConfigureArgs(t *testing.T) {
+	args := BuildOptions{LTO: true, ConfigureOpts: []string{"--with-pydebug"}}.ConfigureArgs("/prefix")
+
+	if !slices.Equal(args, []string{"--prefix=/prefix", "--enable-optimizations", "--with-lto", "--with-pydebug"}) {
+		t.Errorf("Unexpected configure arguments: %v", args)
+	}
+
+	args = BuildOptions{DisablePGO: true}.ConfigureArgs("/prefix")
+
+	if !slices.Equal(args, []string{"--prefix=/prefix"}) {
+		t.Errorf("Unexpected configure arguments: %v", args)
+	}
+}
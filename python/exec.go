@@ -0,0 +1,115 @@
+package python
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	cli "v/cli"
+	state "v/state"
+)
+
+// Replaces the current process, stubbed in tests.
+var execProcess = syscall.Exec
+
+// Commands run through the runtime's interpreter when its bin directory lacks
+// them (i.e. builds made with `make altinstall` only have python3.X), mapped
+// to the arguments passed to the interpreter.
+var interpreterCommands = map[string][]string{
+	"python":  {},
+	"python3": {},
+	"pip":     {"-m", "pip"},
+	"pip3":    {"-m", "pip"},
+}
+
+// Returns <env> with <key> set to <value>, replacing any previous value.
+func setEnv(env []string, key string, value string) []string {
+	env = slices.DeleteFunc(slices.Clone(env), func(entry string) bool {
+		return strings.HasPrefix(entry, key+"=")
+	})
+
+	return append(env, key+"="+value)
+}
+
+// Returns the path to the executable <name>, looked up in the directories
+// listed in <searchPath> unless it is a path already.
+func lookPath(name string, searchPath string) (string, error) {
+	if strings.Contains(name, "/") {
+		return name, nil
+	}
+
+	for _, directory := range filepath.SplitList(searchPath) {
+		candidate := path.Join(directory, name)
+
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.New("Command not found: " + name)
+}
+
+// Exec (called via `v python exec <version> [--install] -- <command> [args]`)
+// runs a command under the given version without changing the selection: the
+// version's bin directory is prepended to PATH, V_PYTHON_VERSION is set so that
+// shims resolve to it, and the command replaces v's process. With --install, a
+// missing version is installed first.
+func execCommand(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+	command := cli.Passthrough(args)
+
+	if len(positional) < 2 || len(command) == 0 {
+		return errors.New("Usage: v python exec <version> [--install] -- <command> [args]")
+	}
+
+	version, err := ResolveAlias(positional[1], currentState.Aliases)
+
+	if err != nil {
+		return err
+	}
+
+	installedVersions, err := ListInstalledVersions()
+
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(installedVersions, version) {
+		if !flags.Install {
+			return errors.New("Python " + version + " is not installed. Pass --install to install it first.")
+		}
+
+		options, err := installOptionsFromFlags(flags)
+
+		if err != nil {
+			return err
+		}
+
+		if err := InstallPythonDistribution(version, options); err != nil {
+			return err
+		}
+	}
+
+	interpreterPath := GetInterpreterPath(version)
+	binPath := path.Dir(interpreterPath)
+	searchPath := binPath + string(os.PathListSeparator) + os.Getenv("PATH")
+
+	var executable string
+
+	if interpreterArgs, found := interpreterCommands[command[0]]; found && !fileExists(path.Join(binPath, command[0])) {
+		executable = interpreterPath
+		command = append(append([]string{interpreterPath}, interpreterArgs...), command[1:]...)
+	} else if executable, err = lookPath(command[0], searchPath); err != nil {
+		return err
+	}
+
+	env := setEnv(os.Environ(), "PATH", searchPath)
+	env = setEnv(env, "V_PYTHON_VERSION", version)
+
+	recordUse(version)
+
+	return execProcess(executable, command, env)
+}
@@ -0,0 +1,133 @@
+package python
+
+import (
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Stubs execProcess, returning the executable, arguments and environment it receives.
+func stubExecProcess(t *testing.T) (*string, *[]string, *[]string) {
+	var executable string
+	var argv, env []string
+
+	previous := execProcess
+	execProcess = func(path string, args []string, environ []string) error {
+		executable, argv, env = path, args, environ
+		return nil
+	}
+	t.Cleanup(func() { execProcess = previous })
+
+	return &executable, &argv, &env
+}
+
+// Sets up <version> as `make altinstall` lays it out: without bin/python,
+// bin/python3 or bin/pip, only bin/python<major>.<minor> and <commands>.
+func setupAltinstallRuntime(t *testing.T, version string, commands ...string) string {
+	binPath := state.GetStatePath("runtimes", "python", version, "bin")
+	os.MkdirAll(binPath, 0750)
+
+	for _, command := range append(commands, "python"+VersionStringToStruct(version).MajorMinor()) {
+		os.WriteFile(path.Join(binPath, command), []byte("#!/bin/sh\n"), 0777)
+	}
+
+	return binPath
+}
+
+func TestExecRunsCommandWithVersionFirstOnPath(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	binPath := setupAltinstallRuntime(t, "1.2.3", "pytest")
+	executable, argv, env := stubExecProcess(t)
+	t.Setenv("V_PYTHON_VERSION", "4.5.6")
+
+	args := []string{"exec", "work", "--", "pytest", "--verbose"}
+	currentState := state.State{GlobalVersion: "1.2.4", Aliases: map[string]string{"work": "1.2.3"}}
+
+	if err := execCommand(args, cli.Flags{}, currentState); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if *executable != path.Join(binPath, "pytest") {
+		t.Errorf("Expected the runtime's command to be run, got %s", *executable)
+	}
+
+	if !slices.Equal(*argv, []string{"pytest", "--verbose"}) {
+		t.Errorf("Unexpected arguments: %v", *argv)
+	}
+
+	if !slices.Contains(*env, "V_PYTHON_VERSION=1.2.3") || slices.Contains(*env, "V_PYTHON_VERSION=4.5.6") {
+		t.Errorf("Expected V_PYTHON_VERSION to be replaced, got %v", *env)
+	}
+
+	if !slices.ContainsFunc(*env, func(entry string) bool { return strings.HasPrefix(entry, "PATH="+binPath+":") }) {
+		t.Errorf("Expected the runtime's bin directory first on PATH, got %v", *env)
+	}
+
+	if _, found := GetLastUsed("1.2.3"); !found {
+		t.Errorf("Expected the use to be recorded.")
+	}
+}
+
+func TestExecRunsAltinstallInterpreterForPythonAndPip(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	binPath := setupAltinstallRuntime(t, "1.2.3")
+	interpreterPath := path.Join(binPath, "python1.2")
+	executable, argv, _ := stubExecProcess(t)
+
+	// Shims on PATH would run the globally selected version instead.
+	shimsPath := state.GetStatePath("shims")
+	os.MkdirAll(shimsPath, 0750)
+	os.WriteFile(path.Join(shimsPath, "python"), []byte("#!/bin/sh\n"), 0777)
+	os.WriteFile(path.Join(shimsPath, "pip"), []byte("#!/bin/sh\n"), 0777)
+	t.Setenv("PATH", shimsPath)
+
+	currentState := state.State{GlobalVersion: "1.2.4"}
+
+	if err := execCommand([]string{"exec", "1.2.3", "--", "python", "-V"}, cli.Flags{}, currentState); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if *executable != interpreterPath || !slices.Equal(*argv, []string{interpreterPath, "-V"}) {
+		t.Errorf("Expected the runtime's interpreter to be run, got %s %v", *executable, *argv)
+	}
+
+	if err := execCommand([]string{"exec", "1.2.3", "--", "pip", "list"}, cli.Flags{}, currentState); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if *executable != interpreterPath || !slices.Equal(*argv, []string{interpreterPath, "-m", "pip", "list"}) {
+		t.Errorf("Expected pip to be run through the runtime's interpreter, got %s %v", *executable, *argv)
+	}
+}
+
+func TestExecReturnsErrorIfNotInstalled(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	executable, _, _ := stubExecProcess(t)
+
+	if err := execCommand([]string{"exec", "1.2.3", "--", "python"}, cli.Flags{}, state.State{}); err == nil {
+		t.Errorf("Expected an error for a missing version.")
+	}
+
+	if *executable != "" {
+		t.Errorf("Did not expect a command to run.")
+	}
+}
+
+func TestExecRequiresCommandAfterSeparator(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	setupAltinstallRuntime(t, "1.2.3")
+	stubExecProcess(t)
+
+	if err := execCommand([]string{"exec", "1.2.3", "python"}, cli.Flags{}, state.State{}); err == nil {
+		t.Errorf("Expected an error without a separator.")
+	}
+}
@@ -0,0 +1,32 @@
+package python
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+	state "v/state"
+)
+
+// Installs empty versions with a manifest, recording them as installed at
+// <installedAt> if passed.
+func installFakeVersions(versions []string, installedAt ...time.Time) {
+	for _, version := range versions {
+		manifest := Manifest{Version: version}
+
+		if len(installedAt) != 0 {
+			manifest.InstalledAt = installedAt[0]
+		}
+
+		os.MkdirAll(state.GetStatePath("runtimes", "python", version), 0750)
+		WriteManifest(version, manifest)
+	}
+}
+
+// Installs a mock version whose interpreter runs <script>.
+func setupScriptedRuntime(t *testing.T, version string, script string) {
+	installPath := state.GetStatePath("runtimes", "python", version)
+	os.MkdirAll(path.Join(installPath, "bin"), 0750)
+	os.WriteFile(path.Join(installPath, "bin", "python"), []byte("#!/bin/sh\n"+script), 0777)
+	WriteManifest(version, Manifest{Version: version, Executable: "bin/python"})
+}
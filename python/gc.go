@@ -0,0 +1,227 @@
+package python
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	cli "v/cli"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Minimum time between two recorded uses of a version, so that shims only
+// write to disk once in a while.
+const lastUsedResolution = time.Hour
+
+// Directories not worth searching for project selections.
+var skippedProjectDirectories = []string{".git", "node_modules"}
+
+// Returns the path to the file recording the last use of <version>. Uses are
+// kept in v's state rather than in install directories, which may belong to
+// another tool (see: `v python link`), and removed on uninstall. One file per
+// version lets concurrent shims record uses without a read-modify-write.
+func getLastUsedPath(version string) string {
+	return state.GetStatePath("usage", "python", version)
+}
+
+// Records that the installed version <version> was just used, as the
+// modification time of its usage file. Failures are ignored, tracking use
+// must never get in the way of running Python.
+func recordUse(version string) {
+	lastUsedPath := getLastUsedPath(version)
+
+	if info, err := os.Stat(lastUsedPath); err == nil && time.Since(info.ModTime()) < lastUsedResolution {
+		return
+	}
+
+	if err := os.WriteFile(lastUsedPath, []byte{}, 0640); os.IsNotExist(err) {
+		os.MkdirAll(path.Dir(lastUsedPath), 0775)
+		os.WriteFile(lastUsedPath, []byte{}, 0640)
+	}
+}
+
+// GetLastUsed returns when the installed version <version> was last used, if
+// it was since use is tracked.
+func GetLastUsed(version string) (time.Time, bool) {
+	info, err := os.Stat(getLastUsedPath(version))
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return info.ModTime(), true
+}
+
+// Returns when <version> was last used or, if it never was, installed.
+func getLastActivity(version string) time.Time {
+	lastActivity, _ := GetLastUsed(version)
+
+	if manifest, err := ReadManifest(version); err == nil && manifest.InstalledAt.After(lastActivity) {
+		lastActivity = manifest.InstalledAt
+	}
+
+	if lastActivity.IsZero() {
+		if info, err := os.Stat(state.GetStatePath("runtimes", "python", version)); err == nil {
+			lastActivity = info.ModTime()
+		}
+	}
+
+	return lastActivity
+}
+
+// Searches <root> for .python-version files and .venv directories, returning
+// the versions they reference keyed by the path referencing them.
+func findProjectReferences(root string) (map[string]string, error) {
+	references := map[string]string{}
+
+	err := filepath.WalkDir(root, func(currentPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if currentPath == root {
+				return err
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() {
+			if slices.Contains(skippedProjectDirectories, entry.Name()) {
+				return filepath.SkipDir
+			}
+
+			if isVenv(currentPath) {
+				references[currentPath] = getVenvVersion(currentPath)
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.Name() == ".python-version" {
+			if content, err := os.ReadFile(currentPath); err == nil {
+				references[currentPath] = strings.TrimSpace(string(content))
+			}
+		}
+
+		return nil
+	})
+
+	return references, err
+}
+
+// Returns the installed versions referenced by the global version, aliases,
+// virtual environments, tools, the current directory or the configured project
+// roots, along with what references them. Aliases are resolved.
+func getReferencedVersions(currentState state.State, projectRoots []string) map[string]string {
+	referenced := getVersionsInUse(currentState)
+
+	reference := func(version string, reason string) {
+		if resolved, err := ResolveAlias(version, currentState.Aliases); err == nil {
+			if _, found := referenced[resolved]; !found {
+				referenced[resolved] = reason
+			}
+		}
+	}
+
+	for name, target := range currentState.Aliases {
+		reference(target, "aliased as "+name)
+	}
+
+	venvs, _ := ListVenvs()
+
+	for _, venv := range venvs {
+		reference(venv.Runtime, "used by virtual environment "+venv.Name)
+	}
+
+	tools, _ := ListTools()
+
+	for _, tool := range tools {
+		reference(tool.Runtime, "used by tool "+tool.Name)
+	}
+
+	for _, root := range projectRoots {
+		references, err := findProjectReferences(root)
+
+		if err != nil {
+			logger.InfoLogger.Println(logger.Yellow("WARNING: Could not search project root " + root + ": " + err.Error()))
+			continue
+		}
+
+		for referencePath, version := range references {
+			reference(version, "referenced by "+referencePath)
+		}
+	}
+
+	return referenced
+}
+
+// Returns the installed versions that are not <referenced> and were not used
+// for at least <unusedFor>.
+func findUnusedVersions(installedVersions []string, referenced map[string]string, unusedFor time.Duration, now time.Time) []string {
+	unused := []string{}
+
+	for _, version := range installedVersions {
+		if _, isReferenced := referenced[version]; isReferenced {
+			continue
+		}
+
+		if now.Sub(getLastActivity(version)) >= unusedFor {
+			unused = append(unused, version)
+		}
+	}
+
+	return unused
+}
+
+// Garbage-collect (called via `v python gc --unused-for <duration> [--dry-run] [--yes]`)
+// uninstalls the versions that were not used for the given duration and that
+// nothing references (see: getReferencedVersions).
+func gc(args []string, flags cli.Flags, currentState state.State) error {
+	if flags.UnusedFor == 0 {
+		return errors.New("Pass --unused-for to set how long versions must have been unused for (i.e. 90d).")
+	}
+
+	config, err := ReadConfig()
+
+	if err != nil {
+		return err
+	}
+
+	installedVersions, err := ListInstalledVersions()
+
+	if err != nil {
+		return err
+	}
+
+	referenced := getReferencedVersions(currentState, config.ProjectRoots)
+	unused := findUnusedVersions(installedVersions, referenced, flags.UnusedFor, time.Now())
+
+	if len(unused) == 0 {
+		logger.InfoLogger.Println("Nothing to garbage-collect.")
+		return nil
+	}
+
+	for _, version := range unused {
+		logger.InfoLogger.Printf("%s (last used or installed %s)\n", version, getLastActivity(version).Format(time.DateOnly))
+	}
+
+	if flags.DryRun {
+		return nil
+	}
+
+	if !flags.Yes && !cli.Confirm("Uninstall these versions?") {
+		return errors.New("Garbage collection cancelled. Pass --yes to skip the confirmation.")
+	}
+
+	for _, version := range unused {
+		if err := uninstallVersion(version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
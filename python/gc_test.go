@@ -0,0 +1,165 @@
+package python
+
+import (
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestRecordUseOnlyWritesOnceInAWhile(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3"})
+
+	if _, found := GetLastUsed("1.2.3"); found {
+		t.Errorf("Did not expect a version never used to have a last use.")
+	}
+
+	recordUse("1.2.3")
+	recent := time.Now().Add(-10 * time.Minute)
+	os.Chtimes(getLastUsedPath("1.2.3"), recent, recent)
+	recordUse("1.2.3")
+
+	if lastUsed, _ := GetLastUsed("1.2.3"); !lastUsed.Equal(recent) {
+		t.Errorf("Expected a recent use not to be recorded again, got %v", lastUsed)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(getLastUsedPath("1.2.3"), old, old)
+	recordUse("1.2.3")
+
+	if lastUsed, _ := GetLastUsed("1.2.3"); time.Since(lastUsed) > time.Minute {
+		t.Errorf("Expected the use to be recorded, got %v", lastUsed)
+	}
+}
+
+func TestWhichRecordsUseAndListShowsIt(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	installFakeVersions([]string{"1.2.3"})
+	out := testutils.CaptureOutput(t)
+
+	which([]string{"which"}, cli.Flags{RawOutput: true}, state.State{GlobalVersion: "1.2.3"})
+	out.Reset()
+	listVersions([]string{"ls"}, cli.Flags{}, state.State{})
+
+	if expected := "1.2.3 (last used " + time.Now().Format(time.DateOnly) + ")\n"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestFindProjectReferencesFindsVersionFilesAndVenvs(t *testing.T) {
+	root := t.TempDir()
+
+	os.MkdirAll(path.Join(root, "a"), 0750)
+	os.WriteFile(path.Join(root, "a", ".python-version"), []byte("1.2.3\n"), 0640)
+	os.MkdirAll(path.Join(root, "b", ".venv"), 0750)
+	os.WriteFile(path.Join(root, "b", ".venv", "pyvenv.cfg"), []byte("version = 1.2.4\n"), 0640)
+	os.MkdirAll(path.Join(root, "node_modules", "c"), 0750)
+	os.WriteFile(path.Join(root, "node_modules", "c", ".python-version"), []byte("1.2.5\n"), 0640)
+
+	references, err := findProjectReferences(root)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		path.Join(root, "a", ".python-version"): "1.2.3",
+		path.Join(root, "b", ".venv"):           "1.2.4",
+	}
+
+	if len(references) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, references)
+	}
+
+	for referencePath, version := range expected {
+		if references[referencePath] != version {
+			t.Errorf("Expected %s to reference %s, got %v", referencePath, version, references)
+		}
+	}
+}
+
+func TestGcRequiresUnusedFor(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	if err := gc([]string{"gc"}, cli.Flags{}, state.State{}); err == nil {
+		t.Errorf("Expected an error without --unused-for.")
+	}
+}
+
+func TestGcUninstallsOnlyUnusedUnreferencedVersions(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	longAgo := time.Now().Add(-200 * 24 * time.Hour)
+	installFakeVersions([]string{"1.2.3", "1.2.4", "1.2.5", "1.2.6", "1.2.7"}, longAgo)
+	installFakeVersions([]string{"1.2.8"}, time.Now())
+	recordUse("1.2.7")
+
+	projectRoot := t.TempDir()
+	os.WriteFile(path.Join(projectRoot, ".python-version"), []byte("1.2.6\n"), 0640)
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"python": {"projectRoots": ["`+projectRoot+`"]}}`), 0640)
+
+	currentState := state.State{GlobalVersion: "1.2.4", Aliases: map[string]string{"work": "1.2.5"}}
+	out := testutils.CaptureOutput(t)
+
+	if err := gc([]string{"gc"}, cli.Flags{UnusedFor: 90 * 24 * time.Hour, DryRun: true}, currentState); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if installed, _ := ListInstalledVersions(); len(installed) != 6 {
+		t.Errorf("Expected --dry-run to keep all versions, got %v", installed)
+	}
+
+	if !strings.HasPrefix(out.String(), "1.2.3 (last used or installed ") {
+		t.Errorf("Expected 1.2.3 to be listed, got %s", out.String())
+	}
+
+	if err := gc([]string{"gc"}, cli.Flags{UnusedFor: 90 * 24 * time.Hour, Yes: true}, currentState); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if installed, _ := ListInstalledVersions(); !slices.Equal(installed, []string{"1.2.4", "1.2.5", "1.2.6", "1.2.7", "1.2.8"}) {
+		t.Errorf("Expected only 1.2.3 to be uninstalled, got %v", installed)
+	}
+}
+
+func TestRecordUseKeepsTimesInState(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	prefix := t.TempDir()
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "linked"), 0750)
+	WriteManifest("linked", Manifest{Version: "linked", Prefix: prefix})
+
+	recordUse("linked")
+
+	if entries, _ := os.ReadDir(prefix); len(entries) != 0 {
+		t.Errorf("Did not expect anything written to the linked prefix, found %v", entries)
+	}
+
+	if _, found := GetLastUsed("linked"); !found {
+		t.Errorf("Expected the use to be recorded in the state directory.")
+	}
+}
+
+func TestUninstallRemovesLastUse(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+
+	installFakeVersions([]string{"1.2.3"})
+	recordUse("1.2.3")
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3"}, cli.Flags{Yes: true}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, found := GetLastUsed("1.2.3"); found {
+		t.Errorf("Expected the last use to be removed with the install.")
+	}
+}
@@ -0,0 +1,156 @@
+package python
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	download "v/download"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+var defaultGitRemote = "https://github.com/python/cpython.git"
+
+// Location of the CPython clone, reused across builds. It is kept out of the
+// cache so that pruning the cache does not throw it away.
+func getGitClonePath() string {
+	return state.GetStatePath("sources", "cpython.git")
+}
+
+// Moves the clone from where earlier versions of v kept it (in the cache), if any.
+func migrateGitClone(clonePath string) error {
+	legacyPath := state.GetStatePath("cache", "cpython-git")
+
+	if _, err := os.Stat(legacyPath); err != nil || fileExists(clonePath) {
+		return nil
+	}
+
+	if err := os.MkdirAll(path.Dir(clonePath), 0775); err != nil {
+		return err
+	}
+
+	return os.Rename(legacyPath, clonePath)
+}
+
+// Fetches <ref> (a branch, tag or commit) from the CPython repository and
+// exports it to a source tree in the cache, ready to be built as <name>.
+//
+// A partial clone (without blobs) is kept in the state directory so that
+// subsequent builds only fetch what changed.
+func checkoutGitSource(name string, options InstallOptions, log *buildLog) (PackageMetadata, error) {
+	logger.InfoLogger.Println(logger.Bold("Fetching CPython " + options.GitRef))
+	logger.InfoLogger.SetPrefix("  ")
+	defer logger.InfoLogger.SetPrefix("")
+
+	if options.Offline {
+		return PackageMetadata{}, errors.New("Building from git requires network access. Use --source-dir with an existing checkout instead.")
+	}
+
+	remote := options.GitRemote
+
+	if remote == "" {
+		remote = defaultGitRemote
+	}
+
+	log.Stage("Fetch")
+
+	clonePath := getGitClonePath()
+	env, err := download.SubprocessEnv()
+
+	if err != nil {
+		return PackageMetadata{}, err
+	}
+
+	commandOptions := exec.CommandOptions{Env: env, Log: log}
+
+	if err := migrateGitClone(clonePath); err != nil {
+		return PackageMetadata{}, err
+	}
+
+	if _, err := os.Stat(clonePath); os.IsNotExist(err) {
+		logger.InfoLogger.Println("Cloning " + remote)
+
+		if err := os.MkdirAll(path.Dir(clonePath), 0775); err != nil {
+			return PackageMetadata{}, err
+		}
+
+		if _, err := exec.RunCommandWithOptions([]string{"git", "clone", "--bare", "--filter=blob:none", remote, clonePath}, state.GetStatePath(), commandOptions); err != nil {
+			return PackageMetadata{}, err
+		}
+	}
+
+	logger.InfoLogger.Println("Fetching " + options.GitRef)
+
+	if _, err := exec.RunCommandWithOptions([]string{"git", "fetch", remote, options.GitRef}, clonePath, commandOptions); err != nil {
+		return PackageMetadata{}, errors.New("Failed to fetch " + options.GitRef + " from " + remote)
+	}
+
+	commit, err := exec.RunCommandWithOptions([]string{"git", "rev-parse", "FETCH_HEAD"}, clonePath, commandOptions)
+
+	if err != nil {
+		return PackageMetadata{}, err
+	}
+
+	commit = strings.TrimSpace(commit)
+	sourcePath := state.GetStatePath("cache", "Python-"+name)
+
+	logger.InfoLogger.Printf("Exporting %s (%s)\n", options.GitRef, commit)
+	log.Stage("Export")
+
+	if err := os.RemoveAll(sourcePath); err != nil {
+		return PackageMetadata{}, err
+	}
+
+	if err := os.MkdirAll(sourcePath, 0775); err != nil {
+		return PackageMetadata{}, err
+	}
+
+	exportPath := sourcePath + ".tar"
+	defer os.Remove(exportPath)
+
+	if _, err := exec.RunCommandWithOptions([]string{"git", "archive", "--format=tar", "-o", exportPath, commit}, clonePath, commandOptions); err != nil {
+		return PackageMetadata{}, err
+	}
+
+	if _, err := exec.RunCommandWithOptions([]string{"tar", "-xf", exportPath, "-C", sourcePath}, clonePath, commandOptions); err != nil {
+		return PackageMetadata{}, err
+	}
+
+	return PackageMetadata{
+		Version:    name,
+		SourcePath: sourcePath,
+		SourceURL:  remote,
+		GitRef:     options.GitRef,
+		GitCommit:  commit,
+	}, nil
+}
+
+// Uses an existing CPython checkout at <sourceDir> as the source of the
+// build <name>. The checkout is built in place and left untouched afterwards.
+func useSourceDirectory(name string, sourceDir string, log *buildLog) (PackageMetadata, error) {
+	absoluteSourceDir, err := filepath.Abs(sourceDir)
+
+	if err != nil {
+		return PackageMetadata{}, err
+	}
+
+	if _, err := os.Stat(filepath.Join(absoluteSourceDir, "configure")); err != nil {
+		return PackageMetadata{}, errors.New(absoluteSourceDir + " does not look like a CPython checkout (no configure script found).")
+	}
+
+	logger.InfoLogger.Println(logger.Bold("Using source from " + absoluteSourceDir))
+	log.Stage("Source")
+	fmt.Fprintln(log, "Building from "+absoluteSourceDir)
+
+	pkgMeta := PackageMetadata{Version: name, SourcePath: absoluteSourceDir, SourceURL: download.FileURL(absoluteSourceDir), KeepSource: true}
+
+	if commit, err := exec.RunCommand([]string{"git", "rev-parse", "HEAD"}, absoluteSourceDir); err == nil {
+		pkgMeta.GitCommit = strings.TrimSpace(commit)
+	}
+
+	return pkgMeta, nil
+}
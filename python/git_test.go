@@ -0,0 +1,131 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cache "v/cache"
+	exec "v/exec"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Creates a git repository with a single commit containing a configure script.
+func createMockCPythonRepository(t *testing.T) string {
+	repositoryPath := t.TempDir()
+	os.WriteFile(path.Join(repositoryPath, "configure"), []byte("#!/bin/sh\n"), 0750)
+
+	for _, command := range [][]string{
+		{"git", "init", "-q", "-b", "main"},
+		{"git", "add", "."},
+		{"git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "Initial commit"},
+	} {
+		if _, err := exec.RunCommand(command, repositoryPath); err != nil {
+			t.Fatalf("Failed to set up repository: %v", err)
+		}
+	}
+
+	return repositoryPath
+}
+
+func TestCheckoutGitSourceExportsRef(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	repositoryPath := createMockCPythonRepository(t)
+
+	pkgMeta, err := checkoutGitSource("main-20261018", InstallOptions{GitRef: "main", GitRemote: repositoryPath}, log)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if pkgMeta.SourcePath != state.GetStatePath("cache", "Python-main-20261018") || pkgMeta.KeepSource {
+		t.Errorf("Unexpected source tree: %v", pkgMeta)
+	}
+
+	if _, err := os.Stat(path.Join(pkgMeta.SourcePath, "configure")); err != nil {
+		t.Errorf("Expected ref to be exported to %s", pkgMeta.SourcePath)
+	}
+
+	expectedCommit, _ := exec.RunCommand([]string{"git", "rev-parse", "HEAD"}, repositoryPath)
+
+	if pkgMeta.GitCommit != strings.TrimSpace(expectedCommit) || pkgMeta.GitRef != "main" {
+		t.Errorf("Unexpected git details: %v", pkgMeta)
+	}
+}
+
+func TestGitCloneSurvivesCachePruning(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	repositoryPath := createMockCPythonRepository(t)
+
+	if _, err := checkoutGitSource("main-20261018", InstallOptions{GitRef: "main", GitRemote: repositoryPath}, log); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := cache.Prune(0, 1); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path.Join(getGitClonePath(), "HEAD")); err != nil {
+		t.Errorf("Expected the clone to be kept by cache pruning.")
+	}
+}
+
+func TestCheckoutGitSourceMovesCloneOutOfCache(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	repositoryPath := createMockCPythonRepository(t)
+	legacyPath := state.GetStatePath("cache", "cpython-git")
+
+	if _, err := exec.RunCommand([]string{"git", "clone", "-q", "--bare", repositoryPath, legacyPath}, t.TempDir()); err != nil {
+		t.Fatalf("Failed to set up clone: %v", err)
+	}
+
+	if _, err := checkoutGitSource("main-20261018", InstallOptions{GitRef: "main", GitRemote: repositoryPath}, log); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the clone to be moved out of the cache.")
+	}
+
+	if _, err := os.Stat(path.Join(getGitClonePath(), "HEAD")); err != nil {
+		t.Errorf("Expected the clone at %s", getGitClonePath())
+	}
+}
+
+func TestCheckoutGitSourceRefusesOffline(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	if _, err := checkoutGitSource("main", InstallOptions{GitRef: "main", Offline: true}, log); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestUseSourceDirectoryKeepsCheckout(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	repositoryPath := createMockCPythonRepository(t)
+
+	pkgMeta, err := useSourceDirectory("dev", repositoryPath, log)
+
+	if err != nil || pkgMeta.SourcePath != repositoryPath || !pkgMeta.KeepSource || pkgMeta.GitCommit == "" {
+		t.Errorf("Unexpected result: %v (%v)", pkgMeta, err)
+	}
+}
+
+func TestUseSourceDirectoryRejectsNonCPythonDirectory(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	if _, err := useSourceDirectory("dev", t.TempDir(), log); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
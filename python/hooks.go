@@ -0,0 +1,61 @@
+package python
+
+import (
+	"errors"
+	"os"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Returns the path to the hook <name> (i.e. post-install).
+func getHookPath(name string) string {
+	return state.GetStatePath("hooks", name)
+}
+
+// Runs the hook <name> for <version>, if any. Hooks are executables placed in
+// the `hooks` state directory, named after the event they handle:
+// pre-install, post-install, pre-uninstall, post-uninstall and post-use.
+//
+// Hooks receive the following environment variables:
+// V_HOOK (hook name), V_RUNTIME (python), V_VERSION, V_INSTALL_PATH and V_ROOT.
+// An error is returned if the hook fails.
+func runHook(name string, version string, extraEnv ...string) error {
+	hookPath := getHookPath(name)
+	info, err := os.Stat(hookPath)
+
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: Skipping hook " + hookPath + ": not executable."))
+		return nil
+	}
+
+	env := append([]string{
+		"V_HOOK=" + name,
+		"V_RUNTIME=python",
+		"V_VERSION=" + version,
+		"V_INSTALL_PATH=" + state.GetStatePath("runtimes", "python", version),
+		"V_ROOT=" + state.GetStatePath(),
+	}, extraEnv...)
+
+	if _, err := exec.RunCommandWithOptions([]string{hookPath}, state.GetStatePath(), exec.CommandOptions{Env: env, Log: logger.InfoLogger.Writer()}); err != nil {
+		return errors.New("Hook " + name + " failed: " + err.Error())
+	}
+
+	return nil
+}
+
+// Runs the hook <name> after an operation completed. Failures are reported
+// but do not fail the operation.
+func runPostHook(name string, version string, extraEnv ...string) {
+	if err := runHook(name, version, extraEnv...); err != nil {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: " + err.Error()))
+	}
+}
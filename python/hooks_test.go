@@ -0,0 +1,91 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Writes the hook <name> with the given shell script as its body.
+func writeHook(t *testing.T, name string, body string) {
+	os.MkdirAll(state.GetStatePath("hooks"), 0750)
+	os.WriteFile(getHookPath(name), []byte("#!/bin/sh\n"+body), 0777)
+}
+
+func TestRunHookSkipsMissingHooks(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	if err := runHook("post-install", "1.2.3"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestRunHookPassesEnvironment(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	outputPath := path.Join(t.TempDir(), "env")
+	writeHook(t, "post-install", "echo \"$V_HOOK $V_RUNTIME $V_VERSION $V_INSTALL_PATH\" > "+outputPath)
+
+	if err := runHook("post-install", "1.2.3"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "post-install python 1.2.3 " + state.GetStatePath("runtimes", "python", "1.2.3")
+
+	if output, _ := os.ReadFile(outputPath); strings.TrimSpace(string(output)) != expected {
+		t.Errorf("Expected %s, got %s", expected, output)
+	}
+}
+
+func TestFailingPreInstallHookAbortsInstall(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	writeHook(t, "pre-install", "exit 1")
+
+	err := InstallPythonDistribution("1.2.3", InstallOptions{Offline: true})
+
+	if err == nil || !strings.Contains(err.Error(), "Hook pre-install failed") {
+		t.Errorf("Expected the hook failure to abort the install, got %v", err)
+	}
+
+	if _, err := os.Stat(state.GetStatePath("logs", "python", "1.2.3")); !os.IsNotExist(err) {
+		t.Errorf("Did not expect the install to start.")
+	}
+}
+
+func TestFailingPreUninstallHookAbortsUninstall(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+	writeHook(t, "pre-uninstall", "exit 1")
+
+	if err := uninstallPython([]string{"uninstall", "1.2.3"}, cli.Flags{Yes: true}, state.State{}); err == nil {
+		t.Errorf("Expected the hook failure to abort the uninstall.")
+	}
+
+	if _, err := os.Stat(state.GetStatePath("runtimes", "python", "1.2.3")); err != nil {
+		t.Errorf("Expected the runtime to be kept.")
+	}
+}
+
+func TestPostUseHookReceivesSelectedName(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+	outputPath := path.Join(t.TempDir(), "env")
+	writeHook(t, "post-use", "echo \"$V_VERSION $V_SELECTED\" > "+outputPath)
+
+	if err := use([]string{"use", "work"}, cli.Flags{}, state.State{Aliases: map[string]string{"work": "1.2.3"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if output, _ := os.ReadFile(outputPath); strings.TrimSpace(string(output)) != "1.2.3 work" {
+		t.Errorf("Unexpected hook output: %s", output)
+	}
+}
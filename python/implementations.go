@@ -0,0 +1,275 @@
+package python
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"runtime"
+	"time"
+	cache "v/cache"
+	download "v/download"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+var pypyReleasesBaseURL = "https://downloads.python.org/pypy"
+
+var graalpyReleasesBaseURL = "https://github.com/oracle/graalpython/releases/download"
+
+// Matches implementation-qualified versions (i.e. pypy3.10-7.3.15).
+var pypyVersionPattern = regexp.MustCompile(`^pypy(\d+\.\d+)-(\d+\.\d+\.\d+)$`)
+
+// Matches implementation-qualified versions (i.e. graalpy-24.0.0).
+var graalpyVersionPattern = regexp.MustCompile(`^graalpy-(\d+\.\d+\.\d+)$`)
+
+// Alternative Python implementation release, installed from the prebuilt
+// archives published by its maintainers rather than built from source.
+type ImplementationVersion struct {
+	// Implementation name (pypy or graalpy).
+	Implementation string
+	// Version of the Python language implemented, if part of the version.
+	LanguageVersion string
+	// Release of the implementation itself.
+	Release string
+}
+
+// ParseImplementationVersion returns the implementation release described by
+// <version>, and whether <version> is implementation-qualified at all.
+func ParseImplementationVersion(version string) (ImplementationVersion, bool) {
+	if match := pypyVersionPattern.FindStringSubmatch(version); match != nil {
+		return ImplementationVersion{Implementation: "pypy", LanguageVersion: match[1], Release: match[2]}, true
+	}
+
+	if match := graalpyVersionPattern.FindStringSubmatch(version); match != nil {
+		return ImplementationVersion{Implementation: "graalpy", Release: match[1]}, true
+	}
+
+	return ImplementationVersion{}, false
+}
+
+// Returns the name of the archive published for the current platform.
+func (v ImplementationVersion) archiveName() (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", errors.New("Prebuilt " + v.Implementation + " archives are only supported on Linux.")
+	}
+
+	architectures := map[string]map[string]string{
+		"pypy":    {"amd64": "linux64", "arm64": "aarch64"},
+		"graalpy": {"amd64": "linux-amd64", "arm64": "linux-aarch64"},
+	}
+
+	platform, found := architectures[v.Implementation][runtime.GOARCH]
+
+	if !found {
+		return "", errors.New("No prebuilt " + v.Implementation + " archive available for " + runtime.GOARCH + ".")
+	}
+
+	if v.Implementation == "pypy" {
+		return "pypy" + v.LanguageVersion + "-v" + v.Release + "-" + platform + v.archiveExtension(), nil
+	}
+
+	return "graalpy-" + v.Release + "-" + platform + v.archiveExtension(), nil
+}
+
+func (v ImplementationVersion) archiveExtension() string {
+	if v.Implementation == "pypy" {
+		return ".tar.bz2"
+	}
+
+	return ".tar.gz"
+}
+
+// Returns the URL of the archive <archiveName> of this release on the mirror
+// <baseURL>.
+func (v ImplementationVersion) archiveURL(baseURL string, archiveName string) (string, error) {
+	if v.Implementation == "pypy" {
+		return url.JoinPath(baseURL, archiveName)
+	}
+
+	return url.JoinPath(baseURL, "graal-"+v.Release, archiveName)
+}
+
+// Returns the path to the interpreter, relative to the install directory.
+func (v ImplementationVersion) executable() string {
+	if v.Implementation == "pypy" {
+		return path.Join("bin", "pypy"+v.LanguageVersion)
+	}
+
+	return path.Join("bin", "graalpy")
+}
+
+// Installs the prebuilt release of an alternative implementation under the
+// name <version>, alongside CPython installs.
+func installImplementation(version string, implementation ImplementationVersion, options InstallOptions) error {
+	if options.GitRef != "" || options.SourceDir != "" || len(options.Patches) != 0 {
+		return errors.New("--git, --source-dir and --patch only apply to CPython source builds.")
+	}
+
+	log, logErr := newBuildLog(version)
+
+	if logErr != nil {
+		return logErr
+	}
+
+	defer log.Close()
+
+	pkgMeta, err := downloadImplementation(version, implementation, options, log)
+
+	if err == nil {
+		pkgMeta, err = unpackImplementation(pkgMeta, implementation, log)
+	}
+
+	if err != nil {
+		reportInstallFailure(log, err)
+		return err
+	}
+
+	archiveDigest, err := getFileDigest(pkgMeta.ArchivePath)
+
+	if err != nil {
+		return err
+	}
+
+	var defaultPackages *DefaultPackagesResult
+
+	if !options.SkipDefaultPackages {
+		defaultPackages = installDefaultPackages(pkgMeta.Executable, options, log)
+	}
+
+	if err := cache.EnforceSizeLimit(); err != nil {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: Failed to enforce the cache size limit: " + err.Error()))
+	}
+
+	return WriteManifest(version, Manifest{
+		Version:         version,
+		SourceURL:       pkgMeta.SourceURL,
+		ArchiveDigest:   archiveDigest,
+		Executable:      implementation.executable(),
+		DefaultPackages: defaultPackages,
+		VVersion:        ToolVersion,
+		InstalledAt:     time.Now().UTC(),
+		LogPath:         log.Path,
+		Host:            getHostInfo(),
+	})
+}
+
+// Fetches the prebuilt archive of <implementation>. Archives are cached as
+// <version>.<extension> and looked up under their upstream name in the archive
+// directory. Otherwise, they are downloaded from the implementation's mirrors
+// (see: getImplementationMirrors), only local ones in offline mode.
+func downloadImplementation(version string, implementation ImplementationVersion, options InstallOptions, log *buildLog) (PackageMetadata, error) {
+	archiveName, err := implementation.archiveName()
+
+	if err != nil {
+		return PackageMetadata{}, err
+	}
+
+	archivePath := state.GetStatePath("cache", version+implementation.archiveExtension())
+
+	logger.InfoLogger.Println(logger.Bold("Downloading " + version))
+	logger.InfoLogger.SetPrefix("  ")
+	defer logger.InfoLogger.SetPrefix("")
+
+	start := time.Now()
+
+	log.Stage("Download")
+
+	sourceUrl, found, err := findLocalArchive(archiveName, archivePath, options, log)
+
+	if err != nil {
+		return PackageMetadata{}, err
+	}
+
+	if !found {
+		if options.NoCache {
+			download.ClearPartial(archivePath)
+		}
+
+		mirrors, err := getImplementationMirrors(implementation.Implementation)
+
+		if err != nil {
+			return PackageMetadata{}, err
+		}
+
+		err = tryEachMirror(mirrors, options.Offline, func(baseURL string) error {
+			sourceUrl, err = implementation.archiveURL(baseURL, archiveName)
+
+			if err != nil {
+				return err
+			}
+
+			logger.InfoLogger.Println("Fetching from " + sourceUrl)
+			fmt.Fprintln(log, "Fetching from "+sourceUrl)
+
+			if err := download.File(sourceUrl, archivePath); err != nil {
+				fmt.Fprintln(log, "Failed: "+err.Error())
+				return err
+			}
+
+			return nil
+		})
+
+		if err != nil && options.Offline {
+			return PackageMetadata{}, errors.New("Archive " + archiveName + " not available offline. Searched " + describeLocalArchiveSources(options) + " and local mirrors: " + err.Error())
+		}
+
+		if err != nil {
+			return PackageMetadata{}, err
+		}
+	}
+
+	logger.InfoLogger.Printf("✅ Done (%s)\n", time.Since(start))
+	return PackageMetadata{ArchivePath: archivePath, Version: version, SourceURL: sourceUrl}, nil
+}
+
+// Unpacks the prebuilt archive into the install directory and checks that the
+// interpreter runs. For PyPy, which does not ship pip, pip is bootstrapped
+// with ensurepip.
+func unpackImplementation(pkgMeta PackageMetadata, implementation ImplementationVersion, log *buildLog) (PackageMetadata, error) {
+	logger.InfoLogger.Println(logger.Bold("Installing " + pkgMeta.Version))
+	logger.InfoLogger.SetPrefix("  ")
+	defer logger.InfoLogger.SetPrefix("")
+
+	start := time.Now()
+
+	log.Stage("Unpack")
+
+	targetDirectory := state.GetStatePath("runtimes", "python", pkgMeta.Version)
+
+	// Leftovers of an incomplete install would be mixed with the new one.
+	if err := os.RemoveAll(targetDirectory); err != nil {
+		return pkgMeta, err
+	}
+
+	if err := os.MkdirAll(targetDirectory, 0775); err != nil {
+		return pkgMeta, err
+	}
+
+	if _, err := exec.RunCommandWithOptions([]string{"tar", "-xf", pkgMeta.ArchivePath, "--strip-components=1", "-C", targetDirectory}, state.GetStatePath("cache"), exec.CommandOptions{Log: log}); err != nil {
+		return pkgMeta, err
+	}
+
+	pkgMeta.InstallPath = targetDirectory
+	pkgMeta.Executable = path.Join(targetDirectory, implementation.executable())
+
+	log.Stage("Verify")
+
+	if _, err := exec.RunCommandWithOptions([]string{pkgMeta.Executable, "--version"}, targetDirectory, exec.CommandOptions{Log: log}); err != nil {
+		return pkgMeta, errors.New("The interpreter " + pkgMeta.Executable + " could not be run.")
+	}
+
+	if _, err := exec.RunCommandWithOptions([]string{pkgMeta.Executable, "-m", "pip", "--version"}, targetDirectory, exec.CommandOptions{Log: log}); err != nil {
+		logger.InfoLogger.Println("Bootstrapping pip")
+
+		if _, err := exec.RunCommandWithOptions([]string{pkgMeta.Executable, "-m", "ensurepip", "--default-pip"}, targetDirectory, exec.CommandOptions{Log: log}); err != nil {
+			logger.InfoLogger.Println(logger.Yellow("WARNING: Failed to bootstrap pip. See: " + log.Path))
+		}
+	}
+
+	logger.InfoLogger.Printf("✅ Installed %s at %s (%s)\n", pkgMeta.Version, pkgMeta.InstallPath, time.Since(start))
+	return pkgMeta, nil
+}
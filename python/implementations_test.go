@@ -0,0 +1,144 @@
+package python
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+	download "v/download"
+	exec "v/exec"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestParseImplementationVersion(t *testing.T) {
+	if implementation, found := ParseImplementationVersion("pypy3.10-7.3.15"); !found || implementation.Implementation != "pypy" || implementation.LanguageVersion != "3.10" || implementation.Release != "7.3.15" {
+		t.Errorf("Unexpected result for pypy3.10-7.3.15: %v", implementation)
+	}
+
+	if implementation, found := ParseImplementationVersion("graalpy-24.0.0"); !found || implementation.Implementation != "graalpy" || implementation.Release != "24.0.0" {
+		t.Errorf("Unexpected result for graalpy-24.0.0: %v", implementation)
+	}
+
+	for _, version := range []string{"3.11.4", "pypy-7.3.15", "graalpy-24.0", "jython-2.7.3"} {
+		if _, found := ParseImplementationVersion(version); found {
+			t.Errorf("Did not expect %s to be an implementation-qualified version.", version)
+		}
+	}
+}
+
+func TestValidateReleaseVersion(t *testing.T) {
+	for _, version := range []string{"3.11.4", "pypy3.10-7.3.15", "graalpy-24.0.0"} {
+		if err := ValidateReleaseVersion(version); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", version, err)
+		}
+	}
+
+	for _, version := range []string{"3.11", "graalpy-24.0", "main"} {
+		if err := ValidateReleaseVersion(version); err == nil {
+			t.Errorf("Expected %s to be invalid.", version)
+		}
+	}
+}
+
+func TestImplementationArchiveURL(t *testing.T) {
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skip("Archive names are platform-specific.")
+	}
+
+	for version, expected := range map[string]string{
+		"pypy3.10-7.3.15": "https://downloads.python.org/pypy/pypy3.10-v7.3.15-linux64.tar.bz2",
+		"graalpy-24.0.0":  "https://github.com/oracle/graalpython/releases/download/graal-24.0.0/graalpy-24.0.0-linux-amd64.tar.gz",
+	} {
+		implementation, _ := ParseImplementationVersion(version)
+		archiveName, _ := implementation.archiveName()
+		mirrors, _ := getImplementationMirrors(implementation.Implementation)
+		archiveURL, err := implementation.archiveURL(mirrors[0], archiveName)
+
+		if err != nil || archiveURL != expected {
+			t.Errorf("Expected %s, got %s (%v)", expected, archiveURL, err)
+		}
+	}
+}
+
+func TestInstallImplementationFromArchive(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	setupDownloadTest(t)
+
+	if runtime.GOOS != "linux" {
+		t.Skip("Prebuilt archives are only supported on Linux.")
+	}
+
+	// Mock release with an interpreter accepting any arguments.
+	releaseRoot := t.TempDir()
+	os.MkdirAll(path.Join(releaseRoot, "graalpy-24.0.0-linux-amd64", "bin"), 0750)
+	os.WriteFile(path.Join(releaseRoot, "graalpy-24.0.0-linux-amd64", "bin", "graalpy"), []byte("#!/bin/sh\nexit 0\n"), 0750)
+
+	archivePath := path.Join(t.TempDir(), "graalpy-24.0.0-linux-amd64.tar.gz")
+
+	if _, err := exec.RunCommand([]string{"tar", "-czf", archivePath, "graalpy-24.0.0-linux-amd64"}, releaseRoot); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	implementation, _ := ParseImplementationVersion("graalpy-24.0.0")
+
+	if err := installImplementation("graalpy-24.0.0", implementation, InstallOptions{FromArchive: archivePath}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !IsCompleteInstall("graalpy-24.0.0") {
+		t.Errorf("Expected a manifest to be written.")
+	}
+
+	if interpreterPath := GetInterpreterPath("graalpy-24.0.0"); interpreterPath != state.GetStatePath("runtimes", "python", "graalpy-24.0.0", "bin", "graalpy") {
+		t.Errorf("Unexpected interpreter path: %s", interpreterPath)
+	}
+
+	if _, err := os.Stat(state.GetStatePath("cache", "graalpy-24.0.0.tar.gz")); err != nil {
+		t.Errorf("Expected archive to be cached.")
+	}
+}
+
+func TestInstallImplementationRejectsSourceBuildOptions(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	implementation, _ := ParseImplementationVersion("pypy3.10-7.3.15")
+	err := installImplementation("pypy3.10-7.3.15", implementation, InstallOptions{Patches: []string{"fix.patch"}})
+
+	if err == nil || !strings.Contains(err.Error(), "--patch") {
+		t.Errorf("Expected an error about --patch, got %v", err)
+	}
+}
+
+func TestInstallImplementationUsesConfiguredMirror(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	setupDownloadTest(t)
+
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skip("Archive names are platform-specific.")
+	}
+
+	releaseRoot := t.TempDir()
+	os.MkdirAll(path.Join(releaseRoot, "graalpy-24.0.0-linux-amd64", "bin"), 0750)
+	os.WriteFile(path.Join(releaseRoot, "graalpy-24.0.0-linux-amd64", "bin", "graalpy"), []byte("#!/bin/sh\nexit 0\n"), 0750)
+
+	mirrorRoot := t.TempDir()
+	os.MkdirAll(path.Join(mirrorRoot, "graal-24.0.0"), 0750)
+
+	if _, err := exec.RunCommand([]string{"tar", "-czf", path.Join(mirrorRoot, "graal-24.0.0", "graalpy-24.0.0-linux-amd64.tar.gz"), "graalpy-24.0.0-linux-amd64"}, releaseRoot); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"python": {"implementationMirrors": {"graalpy": ["`+download.FileURL(mirrorRoot)+`"]}}}`), 0750)
+
+	implementation, _ := ParseImplementationVersion("graalpy-24.0.0")
+
+	if err := installImplementation("graalpy-24.0.0", implementation, InstallOptions{Offline: true, SkipDefaultPackages: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if manifest, _ := ReadManifest("graalpy-24.0.0"); !strings.HasPrefix(manifest.SourceURL, download.FileURL(mirrorRoot)) {
+		t.Errorf("Expected the archive to come from the configured mirror, got %s", manifest.SourceURL)
+	}
+}
@@ -0,0 +1,270 @@
+package python
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	cli "v/cli"
+	download "v/download"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Version manager whose installs can be imported.
+type importSource struct {
+	// Directory holding one install per version.
+	VersionsDir string
+	// Returns the global version configured in the version manager, if any.
+	GlobalVersion func() string
+}
+
+// Returns the value of the environment variable <envVar>, or <fallback>
+// relative to the home directory.
+func getHomePath(envVar string, fallback ...string) string {
+	if value, found := os.LookupEnv(envVar); found && value != "" {
+		return value
+	}
+
+	home, _ := os.UserHomeDir()
+
+	return path.Join(append([]string{home}, fallback...)...)
+}
+
+// Returns the first version listed in <content>, skipping "system" and
+// empty lines. Lines are of the form "[<prefix> ]<version> [<version>...]".
+func getFirstListedVersion(content string, prefix string) string {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+
+		if prefix != "" {
+			if len(fields) < 2 || fields[0] != prefix {
+				continue
+			}
+
+			fields = fields[1:]
+		}
+
+		if len(fields) != 0 && fields[0] != "system" {
+			return fields[0]
+		}
+	}
+
+	return ""
+}
+
+func getImportSource(name string) (importSource, error) {
+	switch name {
+	case "pyenv":
+		root := getHomePath("PYENV_ROOT", ".pyenv")
+
+		return importSource{
+			VersionsDir: path.Join(root, "versions"),
+			GlobalVersion: func() string {
+				content, _ := os.ReadFile(path.Join(root, "version"))
+				return getFirstListedVersion(string(content), "")
+			},
+		}, nil
+	case "asdf":
+		return importSource{
+			VersionsDir: path.Join(getHomePath("ASDF_DATA_DIR", ".asdf"), "installs", "python"),
+			GlobalVersion: func() string {
+				home, _ := os.UserHomeDir()
+				content, _ := os.ReadFile(path.Join(home, ".tool-versions"))
+				return getFirstListedVersion(string(content), "python")
+			},
+		}, nil
+	}
+
+	return importSource{}, errors.New("Unknown version manager: " + name + ". Expected pyenv or asdf.")
+}
+
+// Returns the scripts in the bin directory of <installPath> whose shebang
+// points to an interpreter under <previousPrefix>.
+func findStaleScripts(installPath string, previousPrefix string) []string {
+	entries, _ := os.ReadDir(path.Join(installPath, "bin"))
+	stale := []string{}
+
+	for _, entry := range entries {
+		file, err := os.Open(path.Join(installPath, "bin", entry.Name()))
+
+		if err != nil {
+			continue
+		}
+
+		header := make([]byte, len("#!")+len(previousPrefix)+1)
+		n, _ := io.ReadFull(file, header)
+		file.Close()
+
+		if strings.HasPrefix(string(header[:n]), "#!"+previousPrefix+"/") {
+			stale = append(stale, entry.Name())
+		}
+	}
+
+	return stale
+}
+
+// Moves the install at <sourcePath> to <installPath>. Installs are not
+// always relocatable (i.e. --enable-shared builds keep an rpath to their
+// prefix): the move is rolled back if the interpreter cannot run once moved.
+func moveInstall(sourcePath string, installPath string, executable string) error {
+	if err := os.Rename(sourcePath, installPath); err != nil {
+		return err
+	}
+
+	if output, err := exec.RunCommand([]string{path.Join(installPath, executable), "-c", "import sys"}, state.GetStatePath()); err != nil {
+		if rollbackErr := os.Rename(installPath, sourcePath); rollbackErr != nil {
+			return errors.New("The interpreter moved to " + installPath + " cannot run (" + err.Error() + ") and moving it back failed: " + rollbackErr.Error())
+		}
+
+		return errors.New("the interpreter cannot run once moved: " + strings.TrimSpace(output+" "+err.Error()))
+	}
+
+	if stale := findStaleScripts(installPath, sourcePath); len(stale) != 0 {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: These scripts still point to " + sourcePath + " and need their packages reinstalled: " + strings.Join(stale, ", ")))
+	}
+
+	return nil
+}
+
+// Imports the install at <sourcePath> as <name>, either by moving it into the
+// runtimes directory or by linking it (see: `v python link`). Installs that
+// cannot be moved are linked instead.
+func importInstall(name string, sourcePath string, sourceName string, move bool) error {
+	interpreterPath, err := findInterpreter(sourcePath)
+
+	if err != nil {
+		return err
+	}
+
+	executable, _ := filepath.Rel(sourcePath, interpreterPath)
+	installPath := state.GetStatePath("runtimes", "python", name)
+	manifest := Manifest{
+		Version:      name,
+		ImportedFrom: sourceName,
+		Executable:   executable,
+		VVersion:     ToolVersion,
+		InstalledAt:  time.Now().UTC(),
+		Host:         getHostInfo(),
+	}
+
+	if move {
+		err := moveInstall(sourcePath, installPath, executable)
+
+		if err == nil {
+			return WriteManifest(name, manifest)
+		}
+
+		if !fileExists(sourcePath) {
+			return err
+		}
+
+		logger.InfoLogger.Println(logger.Yellow("WARNING: Could not move " + sourcePath + " (" + err.Error() + "). Linking it instead."))
+	}
+
+	if err := os.MkdirAll(installPath, 0775); err != nil {
+		return err
+	}
+
+	manifest.SourceURL = download.FileURL(sourcePath)
+	manifest.Prefix = sourcePath
+
+	return WriteManifest(name, manifest)
+}
+
+// ImportVersions (called via `v import <pyenv|asdf>`) brings the Python
+// installs of another version manager under v, without rebuilding them.
+// Installs are linked by default, or moved with --move. The global version of
+// the version manager becomes v's global version. --dry-run previews the import.
+func ImportVersions(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 2 {
+		return errors.New("Missing version manager to import from (pyenv or asdf).")
+	}
+
+	source, err := getImportSource(positional[1])
+
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(source.VersionsDir)
+
+	if err != nil {
+		return errors.New("Cannot read " + source.VersionsDir + ": " + err.Error())
+	}
+
+	if err := os.MkdirAll(state.GetStatePath("runtimes", "python"), 0775); err != nil {
+		return err
+	}
+
+	action := "Linked"
+
+	if flags.Move {
+		action = "Moved"
+	}
+
+	if flags.DryRun {
+		action = "Would import"
+	}
+
+	installedVersions := GetAvailableVersions()
+	imported := []string{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		sourcePath := path.Join(source.VersionsDir, name)
+
+		// pyenv uses symlinks for prefix aliases (i.e. 3.11 -> 3.11.4).
+		if entry.Type()&fs.ModeSymlink != 0 {
+			logger.InfoLogger.Printf("Skipping %s: symlink to another install\n", name)
+			continue
+		}
+
+		if !entry.IsDir() || ValidateInstallName(name) != nil {
+			continue
+		}
+
+		if slices.Contains(installedVersions, name) {
+			logger.InfoLogger.Printf("Skipping %s: already installed\n", name)
+			continue
+		}
+
+		if _, err := findInterpreter(sourcePath); err != nil {
+			logger.InfoLogger.Printf("Skipping %s: %s\n", name, err)
+			continue
+		}
+
+		if !flags.DryRun {
+			if err := importInstall(name, sourcePath, positional[1], flags.Move); err != nil {
+				return err
+			}
+		}
+
+		imported = append(imported, name)
+		logger.InfoLogger.Printf("%s %s from %s\n", action, logger.Bold(name), sourcePath)
+	}
+
+	globalVersion := source.GlobalVersion()
+
+	if globalVersion == "" || !slices.Contains(append(installedVersions, imported...), globalVersion) {
+		return nil
+	}
+
+	if flags.DryRun {
+		logger.InfoLogger.Printf("Would set the global version to %s\n", globalVersion)
+		return nil
+	}
+
+	state.WriteState(globalVersion)
+	logger.InfoLogger.Printf("Global version set to %s\n", globalVersion)
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Creates a mock pyenv root holding installs of <versions>, with <globalVersion>
+// as its global version.
+func setupPyenvRoot(t *testing.T, versions []string, globalVersion string) string {
+	root := t.TempDir()
+
+	for _, version := range versions {
+		os.MkdirAll(path.Join(root, "versions", version, "bin"), 0750)
+		os.WriteFile(path.Join(root, "versions", version, "bin", "python"+VersionStringToStruct(version).MajorMinor()), []byte("#!/bin/sh\n"), 0750)
+	}
+
+	os.WriteFile(path.Join(root, "version"), []byte(globalVersion+"\n"), 0640)
+	t.Setenv("PYENV_ROOT", root)
+
+	return root
+}
+
+func TestGetFirstListedVersion(t *testing.T) {
+	if version := getFirstListedVersion("system\n3.11.4\n", ""); version != "3.11.4" {
+		t.Errorf("Expected 3.11.4, got %s", version)
+	}
+
+	if version := getFirstListedVersion("nodejs 20.0.0\npython 3.10.0 3.11.4\n", "python"); version != "3.10.0" {
+		t.Errorf("Expected 3.10.0, got %s", version)
+	}
+}
+
+func TestImportVersionsLinksPyenvInstalls(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	root := setupPyenvRoot(t, []string{"3.10.0", "3.11.4"}, "3.11.4")
+	os.Symlink(path.Join(root, "versions", "3.11.4"), path.Join(root, "versions", "3.11"))
+
+	if err := ImportVersions([]string{"import", "pyenv"}, cli.Flags{}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if interpreterPath := GetInterpreterPath("3.11.4"); interpreterPath != path.Join(root, "versions", "3.11.4", "bin", "python3.11") {
+		t.Errorf("Unexpected interpreter path: %s", interpreterPath)
+	}
+
+	if IsCompleteInstall("3.11") {
+		t.Errorf("Did not expect symlinked versions to be imported.")
+	}
+
+	if globalVersion := state.ReadState().GlobalVersion; globalVersion != "3.11.4" {
+		t.Errorf("Expected the global version to be carried over, got %s", globalVersion)
+	}
+}
+
+func TestImportVersionsMovesInstalls(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	root := setupPyenvRoot(t, []string{"3.10.0"}, "system")
+
+	if err := ImportVersions([]string{"import", "pyenv"}, cli.Flags{Move: true}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path.Join(root, "versions", "3.10.0")); !os.IsNotExist(err) {
+		t.Errorf("Expected the install to be moved.")
+	}
+
+	if interpreterPath := GetInterpreterPath("3.10.0"); interpreterPath != state.GetStatePath("runtimes", "python", "3.10.0", "bin", "python3.10") {
+		t.Errorf("Unexpected interpreter path: %s", interpreterPath)
+	}
+
+	if globalVersion := state.ReadState().GlobalVersion; globalVersion != "" {
+		t.Errorf("Did not expect a global version, got %s", globalVersion)
+	}
+}
+
+func TestImportVersionsWarnsAboutScriptsPointingToMovedInstall(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	root := setupPyenvRoot(t, []string{"3.10.0"}, "system")
+	sourcePath := path.Join(root, "versions", "3.10.0")
+	os.WriteFile(path.Join(sourcePath, "bin", "pytest"), []byte("#!"+sourcePath+"/bin/python3.10\n"), 0750)
+
+	if err := ImportVersions([]string{"import", "pyenv"}, cli.Flags{Move: true}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "still point to "+sourcePath) || !strings.Contains(out.String(), "pytest") {
+		t.Errorf("Expected a warning about pytest, got %s", out.String())
+	}
+
+	if manifest, _ := ReadManifest("3.10.0"); manifest.SourceURL != "" || manifest.Prefix != "" {
+		t.Errorf("Did not expect the moved install to point to its previous location: %v", manifest)
+	}
+}
+
+func TestImportVersionsLinksInstallsThatCannotBeMoved(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	root := setupPyenvRoot(t, []string{"3.10.0"}, "system")
+	sourcePath := path.Join(root, "versions", "3.10.0")
+	// Mimics an interpreter only able to run from its original prefix.
+	os.WriteFile(path.Join(sourcePath, "bin", "python3.10"), []byte("#!/bin/sh\ncase \"$0\" in "+root+"/*) exit 0;; *) exit 1;; esac\n"), 0750)
+
+	if err := ImportVersions([]string{"import", "pyenv"}, cli.Flags{Move: true}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path.Join(sourcePath, "bin", "python3.10")); err != nil {
+		t.Errorf("Expected the move to be rolled back.")
+	}
+
+	if manifest, _ := ReadManifest("3.10.0"); manifest.Prefix != sourcePath {
+		t.Errorf("Expected the install to be linked instead, got %v", manifest)
+	}
+}
+
+func TestImportVersionsDryRunChangesNothing(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	setupPyenvRoot(t, []string{"3.10.0"}, "3.10.0")
+
+	if err := ImportVersions([]string{"import", "pyenv"}, cli.Flags{DryRun: true}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(GetAvailableVersions()) != 0 || state.ReadState().GlobalVersion != "" {
+		t.Errorf("Expected nothing to be imported.")
+	}
+
+	if !strings.Contains(out.String(), "Would import") || !strings.Contains(out.String(), "Would set the global version to 3.10.0") {
+		t.Errorf("Expected a preview, got %s", out.String())
+	}
+}
@@ -2,24 +2,39 @@ package python
 
 import (
 	"errors"
-	"io"
-	"net/http"
+	"fmt"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	cache "v/cache"
+	download "v/download"
 	exec "v/exec"
 	logger "v/logger"
 	state "v/state"
 )
 
-var pythonReleasesBaseURL = "https://www.python.org/ftp/python"
-
 type PackageMetadata struct {
 	ArchivePath string
 	InstallPath string
 	Version     string
+	SourceURL   string
+	// Source tree to build from. If empty, the archive is unpacked first.
+	SourcePath string
+	// Interpreter built, set once the build completes.
+	Executable string
+	// Whether the source tree belongs to the user and must not be removed
+	// after the build.
+	KeepSource bool
+	GitRef     string
+	GitCommit  string
+	// Patches to apply before configuring, and the ones applied.
+	Patches        []string
+	AppliedPatches []AppliedPatch
 }
 
 type VersionTag struct {
@@ -39,29 +54,322 @@ func (t VersionTag) MajorMinor() string {
 //
 // The tarball is cached in the `cache` state directory and is reused
 // if the same version is installed again later.
-func InstallPythonDistribution(version string, noCache bool) error {
-	if err := ValidateVersion(version); err != nil {
+//
+// Implementation-qualified versions (i.e. pypy3.10-7.3.15, graalpy-24.0.0)
+// are installed from prebuilt archives instead (see: installImplementation).
+//
+// Development builds can instead be made from a git ref of CPython
+// (--git) or from an existing checkout (--source-dir). In that case,
+// <version> is the name the build is installed under.
+//
+// The output of every stage is captured in a log file under the `logs`
+// state directory (see: `v python logs <version>`).
+//
+// The pre-install and post-install hooks run around the install (see: runHook).
+func InstallPythonDistribution(version string, options InstallOptions) error {
+	isDevBuild := options.GitRef != "" || options.SourceDir != ""
+
+	if isDevBuild {
+		if err := ValidateInstallName(version); err != nil {
+			return err
+		}
+	} else if err := ValidateReleaseVersion(version); err != nil {
+		return err
+	}
+
+	if err := runHook("pre-install", version); err != nil {
+		return err
+	}
+
+	var err error
+
+	if implementation, found := ParseImplementationVersion(version); found && !isDevBuild {
+		err = installImplementation(version, implementation, options)
+	} else {
+		err = buildPythonDistribution(version, options)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	runPostHook("post-install", version)
+
+	return nil
+}
+
+// Builds CPython from a release tarball, a git ref or a checkout.
+func buildPythonDistribution(version string, options InstallOptions) error {
+	log, logErr := newBuildLog(version)
+
+	if logErr != nil {
+		return logErr
+	}
+
+	defer log.Close()
+
+	var packageMetadata PackageMetadata
+	var archiveDigest string
+	var sourceErr error
+
+	switch {
+	case options.GitRef != "":
+		packageMetadata, sourceErr = checkoutGitSource(version, options, log)
+	case options.SourceDir != "":
+		packageMetadata, sourceErr = useSourceDirectory(version, options.SourceDir, log)
+	default:
+		packageMetadata, sourceErr = downloadSource(version, options, log)
+
+		if sourceErr == nil {
+			archiveDigest, sourceErr = getFileDigest(packageMetadata.ArchivePath)
+		}
+	}
+
+	if sourceErr == nil {
+		packageMetadata.Patches, sourceErr = collectPatches(version, options.Patches)
+	}
+
+	if sourceErr == nil && len(packageMetadata.Patches) != 0 && packageMetadata.KeepSource {
+		sourceErr = errors.New("Patches cannot be applied to a checkout passed with --source-dir. Apply them to the checkout instead.")
+	}
+
+	if sourceErr != nil {
+		reportInstallFailure(log, sourceErr)
+		return sourceErr
+	}
+
+	buildStart := time.Now()
+
+	if err := setAsidePreviousInstall(version); err != nil {
+		reportInstallFailure(log, err)
+		return err
+	}
+
+	packageMetadata, err := buildFromSource(packageMetadata, options.Build, log)
+
+	if err != nil {
+		if restoreErr := restorePreviousInstall(version); restoreErr != nil {
+			err = errors.New(err.Error() + " Restoring the previous install failed: " + restoreErr.Error())
+		}
+
+		reportInstallFailure(log, err)
+		return err
+	}
+
+	if err := os.RemoveAll(getPreviousInstallPath(version)); err != nil {
+		return err
+	}
+
+	var defaultPackages *DefaultPackagesResult
+
+	if !options.SkipDefaultPackages {
+		defaultPackages = installDefaultPackages(packageMetadata.Executable, options, log)
+	}
+
+	if err := cache.EnforceSizeLimit(); err != nil {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: Failed to enforce the cache size limit: " + err.Error()))
+	}
+
+	executable, _ := filepath.Rel(packageMetadata.InstallPath, packageMetadata.Executable)
+
+	return WriteManifest(version, Manifest{
+		Version:         version,
+		SourceURL:       packageMetadata.SourceURL,
+		ArchiveDigest:   archiveDigest,
+		GitRef:          packageMetadata.GitRef,
+		GitCommit:       packageMetadata.GitCommit,
+		Executable:      executable,
+		Patches:         packageMetadata.AppliedPatches,
+		BuildOptions:    options.Build,
+		DefaultPackages: defaultPackages,
+		VVersion:        ToolVersion,
+		InstalledAt:     time.Now().UTC(),
+		BuildDuration:   time.Since(buildStart).Round(time.Second),
+		LogPath:         log.Path,
+		Host:            getHostInfo(),
+	})
+}
+
+// Returns where the install of <version> is set aside during a reinstall.
+func getPreviousInstallPath(version string) string {
+	return state.GetStatePath("runtimes", ".previous", "python", version)
+}
+
+// Sets the install of <version> aside, if any, so that a failed reinstall can
+// restore it. Builds are tied to their prefix: the new one cannot be built
+// elsewhere and moved into place once verified.
+func setAsidePreviousInstall(version string) error {
+	installPath := state.GetStatePath("runtimes", "python", version)
+
+	if !fileExists(installPath) {
+		return nil
+	}
+
+	previousPath := getPreviousInstallPath(version)
+
+	if err := os.RemoveAll(previousPath); err != nil {
 		return err
 	}
 
-	packageMetadata, dlerr := downloadSource(version, noCache)
+	if err := os.MkdirAll(path.Dir(previousPath), 0775); err != nil {
+		return err
+	}
+
+	return os.Rename(installPath, previousPath)
+}
+
+// Replaces whatever a failed reinstall of <version> left behind by the install
+// set aside before it (see: setAsidePreviousInstall), if any.
+func restorePreviousInstall(version string) error {
+	previousPath := getPreviousInstallPath(version)
+
+	if !fileExists(previousPath) {
+		return nil
+	}
+
+	installPath := state.GetStatePath("runtimes", "python", version)
+
+	if err := os.RemoveAll(installPath); err != nil {
+		return err
+	}
+
+	return os.Rename(previousPath, installPath)
+}
+
+// Records the failure in the build log and prints the last lines of
+// the log along with its location.
+func reportInstallFailure(log *buildLog, failure error) {
+	fmt.Fprintf(log, "\nInstall failed: %s\n", failure)
+
+	lines, _ := tailFile(log.Path, failureLogTailLength)
+
+	logger.InfoLogger.Println(logger.Bold(logger.Yellow("Install failed. Last lines of the build log:")))
+
+	for _, line := range lines {
+		logger.InfoLogger.Println("  " + line)
+	}
+
+	logger.InfoLogger.Println(logger.Bold("Full log: " + log.Path))
+}
+
+// Matches the name of CPython release archives, capturing the version.
+var archiveNamePattern = regexp.MustCompile(`^Python-(\d+\.\d+\.\d+)\.(tgz|tar\.gz)$`)
+
+// Returns the version of the CPython release archive at <archivePath>, based
+// on its name (Python-<version>.tgz).
+func getArchiveVersion(archivePath string) (string, error) {
+	match := archiveNamePattern.FindStringSubmatch(path.Base(archivePath))
+
+	if match == nil {
+		return "", errors.New("Cannot determine the version of " + archivePath + ". Expected a name like Python-<version>.tgz, or pass the version explicitly.")
+	}
+
+	return match[1], nil
+}
+
+// Returns the top-level directory of the gzipped tarball at <archivePath>,
+// under which all its entries are expected to be.
+func getArchiveRoot(archivePath string) (string, error) {
+	output, err := exec.RunCommand([]string{"tar", "-tzf", archivePath}, "")
+
+	if err != nil {
+		return "", errors.New("Cannot read archive " + archivePath + ": " + strings.TrimSpace(output))
+	}
+
+	root := ""
+
+	for _, entry := range strings.Split(strings.TrimSpace(output), "\n") {
+		entryRoot, _, _ := strings.Cut(strings.TrimPrefix(entry, "./"), "/")
+
+		if entryRoot == "" {
+			continue
+		}
+
+		if root != "" && entryRoot != root {
+			return "", errors.New("Archive " + archivePath + " has no single top-level directory.")
+		}
 
-	if dlerr != nil {
-		return dlerr
+		root = entryRoot
 	}
 
-	if _, err := buildFromSource(packageMetadata); err != nil {
+	return root, nil
+}
+
+// Returns an error if the archive passed via --from-archive does not hold
+// the sources of <version> (under Python-<version>), so that a mismatched
+// archive never ends up cached as that version.
+func checkLocalArchive(archivePath string, version string) error {
+	root, err := getArchiveRoot(archivePath)
+
+	if err != nil {
 		return err
 	}
 
+	if expected := "Python-" + version; root != expected {
+		return errors.New("Archive " + archivePath + " holds " + root + ", expected " + expected + ". Pass the version it holds, or repack it under " + expected + ".")
+	}
+
 	return nil
 }
 
-// Fetches the Python tarball for version <version> from python.org.
-func downloadSource(version string, skipCache bool) (PackageMetadata, error) {
+// Makes the archive <archiveName> available at <archivePath> without
+// downloading it: from the local archive passed via --from-archive, from the
+// cache or, in offline mode, from the archive directory. The URL the archive
+// was copied from (empty if cached) and whether it was found are returned.
+func findLocalArchive(archiveName string, archivePath string, options InstallOptions, log *buildLog) (string, bool, error) {
+	if options.FromArchive != "" {
+		absoluteArchivePath, _ := filepath.Abs(options.FromArchive)
+		sourceUrl := download.FileURL(absoluteArchivePath)
+
+		logger.InfoLogger.Println("Using local archive " + absoluteArchivePath)
+		fmt.Fprintln(log, "Using local archive "+absoluteArchivePath)
+
+		return sourceUrl, true, download.File(sourceUrl, archivePath)
+	}
+
+	if fileExists(archivePath) && (!options.NoCache || options.Offline) {
+		if options.NoCache {
+			logger.InfoLogger.Println(logger.Yellow("Offline mode: using the cached archive despite --no-cache."))
+		}
+
+		logger.InfoLogger.Println("Found in cache: " + archivePath)
+		fmt.Fprintln(log, "Found in cache: "+archivePath)
+
+		return "", true, nil
+	}
+
+	if localArchive := path.Join(options.ArchiveDir, archiveName); options.Offline && options.ArchiveDir != "" && fileExists(localArchive) {
+		sourceUrl := download.FileURL(localArchive)
+
+		logger.InfoLogger.Println("Found in archive directory: " + localArchive)
+		fmt.Fprintln(log, "Found in archive directory: "+localArchive)
+
+		return sourceUrl, true, download.File(sourceUrl, archivePath)
+	}
+
+	return "", false, nil
+}
+
+// Describes the places findLocalArchive looks into, for error messages.
+func describeLocalArchiveSources(options InstallOptions) string {
+	searched := "the cache (" + state.GetStatePath("cache") + ")"
+
+	if options.ArchiveDir != "" {
+		searched += ", the archive directory (" + options.ArchiveDir + ")"
+	}
+
+	return searched
+}
+
+// Fetches the Python tarball for version <version>.
+//
+// The archive is taken from the local archive passed via --from-archive if
+// any, or from the cache. Otherwise, it is downloaded from the configured
+// mirrors (python.org by default) in order. In offline mode, only the archive
+// directory and local (file://) mirrors are used.
+func downloadSource(version string, options InstallOptions, log *buildLog) (PackageMetadata, error) {
 	archiveName := "Python-" + version + ".tgz"
 	archivePath := state.GetStatePath("cache", archiveName)
-	sourceUrl, _ := url.JoinPath(pythonReleasesBaseURL, version, archiveName)
 
 	logger.InfoLogger.Println(logger.Bold("Downloading source for Python " + version))
 	logger.InfoLogger.SetPrefix("  ")
@@ -69,44 +377,105 @@ func downloadSource(version string, skipCache bool) (PackageMetadata, error) {
 
 	start := time.Now()
 
-	if _, err := os.Stat(archivePath); errors.Is(err, os.ErrNotExist) || skipCache {
-		logger.InfoLogger.Println("Fetching from " + sourceUrl)
+	log.Stage("Download")
 
-		resp, err := http.Get(sourceUrl)
-
-		if err != nil {
+	if options.FromArchive != "" {
+		if err := checkLocalArchive(options.FromArchive, version); err != nil {
 			return PackageMetadata{}, err
 		}
+	}
 
-		defer resp.Body.Close()
-		file, _ := os.Create(archivePath)
-		io.Copy(file, resp.Body)
+	sourceUrl, found, err := findLocalArchive(archiveName, archivePath, options, log)
 
-		defer file.Close()
-	} else {
-		logger.InfoLogger.Println("Found in cache: " + archivePath)
+	if err != nil {
+		return PackageMetadata{}, err
+	}
+
+	if !found {
+		if options.NoCache {
+			download.ClearPartial(archivePath)
+		}
+
+		// Partial data is only resumed from the mirror it was downloaded from
+		// (see: download.File), on this run or the next.
+		err := tryMirrors(options.Offline, func(baseURL string) error {
+			sourceUrl, _ = url.JoinPath(baseURL, version, archiveName)
+
+			logger.InfoLogger.Println("Fetching from " + sourceUrl)
+			fmt.Fprintln(log, "Fetching from "+sourceUrl)
+
+			if err := download.File(sourceUrl, archivePath); err != nil {
+				fmt.Fprintln(log, "Failed: "+err.Error())
+				return err
+			}
+
+			return nil
+		})
+
+		if err != nil && options.Offline {
+			return PackageMetadata{}, errors.New("Archive " + archiveName + " not available offline. Searched " + describeLocalArchiveSources(options) + " and local mirrors: " + err.Error())
+		}
+
+		if err != nil {
+			return PackageMetadata{}, err
+		}
 	}
 
 	logger.InfoLogger.Printf("✅ Done (%s)\n", time.Since(start))
-	return PackageMetadata{ArchivePath: archivePath, Version: version}, nil
+	return PackageMetadata{ArchivePath: archivePath, Version: version, SourceURL: sourceUrl}, nil
 }
 
-func buildFromSource(pkgMeta PackageMetadata) (PackageMetadata, error) {
+func fileExists(filePath string) bool {
+	_, err := os.Stat(filePath)
+
+	return err == nil
+}
+
+func buildFromSource(pkgMeta PackageMetadata, options BuildOptions, log *buildLog) (PackageMetadata, error) {
 	logger.InfoLogger.Println(logger.Bold("Building from source"))
 	logger.InfoLogger.SetPrefix("  ")
 	defer logger.InfoLogger.SetPrefix("")
 
 	start := time.Now()
 
-	logger.InfoLogger.Println("Unpacking source for " + pkgMeta.ArchivePath)
+	if pkgMeta.SourcePath == "" {
+		logger.InfoLogger.Println("Unpacking source for " + pkgMeta.ArchivePath)
+		log.Stage("Unpack")
+
+		unzippedRoot := strings.TrimSuffix(pkgMeta.ArchivePath, path.Ext(pkgMeta.ArchivePath))
+
+		// Trees left behind by failed builds may have been modified (i.e. patched).
+		if err := os.RemoveAll(unzippedRoot); err != nil {
+			return pkgMeta, err
+		}
 
-	if _, untarErr := exec.RunCommand([]string{"tar", "zxvf", pkgMeta.ArchivePath}, state.GetStatePath("cache")); untarErr != nil {
-		return pkgMeta, untarErr
+		if _, untarErr := exec.RunCommandWithOptions([]string{"tar", "zxvf", pkgMeta.ArchivePath}, state.GetStatePath("cache"), exec.CommandOptions{Log: log}); untarErr != nil {
+			return pkgMeta, untarErr
+		}
+
+		pkgMeta.SourcePath = unzippedRoot
 	}
 
-	unzippedRoot := strings.TrimSuffix(pkgMeta.ArchivePath, path.Ext(pkgMeta.ArchivePath))
+	sourceRoot := pkgMeta.SourcePath
+
+	if len(pkgMeta.Patches) != 0 {
+		log.Stage("Patch")
+
+		appliedPatches, patchErr := applyPatches(sourceRoot, pkgMeta.Patches, log)
+
+		if patchErr != nil {
+			return pkgMeta, patchErr
+		}
+
+		pkgMeta.AppliedPatches = appliedPatches
+	}
+
+	logger.InfoLogger.Println("Checking build dependencies")
+
+	checkBuildDependencies(options.EnvList())
 
 	logger.InfoLogger.Println("Configuring installer")
+	log.Stage("Configure")
 
 	if _, err := os.Stat(state.GetStatePath("runtimes", "python")); os.IsNotExist(err) {
 		os.Mkdir(state.GetStatePath("runtimes", "python"), 0775)
@@ -114,22 +483,47 @@ func buildFromSource(pkgMeta PackageMetadata) (PackageMetadata, error) {
 
 	targetDirectory := state.GetStatePath("runtimes", "python", pkgMeta.Version)
 
-	if _, configureErr := exec.RunCommand([]string{"./configure", "--prefix=" + targetDirectory, "--enable-optimizations"}, unzippedRoot); configureErr != nil {
+	commandOptions := exec.CommandOptions{Env: options.EnvList(), Log: log}
+
+	if _, configureErr := exec.RunCommandWithOptions(append([]string{"./configure"}, options.ConfigureArgs(targetDirectory)...), sourceRoot, commandOptions); configureErr != nil {
 		return pkgMeta, configureErr
 	}
 
-	logger.InfoLogger.Println("Building")
+	logger.InfoLogger.Printf("Building (%d jobs)\n", options.Jobs)
+	log.Stage("Build")
 
-	if _, buildErr := exec.RunCommand([]string{"make", "altinstall", "-j4"}, unzippedRoot); buildErr != nil {
+	if _, buildErr := exec.RunCommandWithOptions([]string{"make", "altinstall", "-j" + strconv.Itoa(options.Jobs)}, sourceRoot, commandOptions); buildErr != nil {
 		return pkgMeta, buildErr
 	}
 
-	if cleanupErr := os.RemoveAll(unzippedRoot); cleanupErr != nil {
-		return pkgMeta, cleanupErr
+	if !pkgMeta.KeepSource {
+		if cleanupErr := os.RemoveAll(sourceRoot); cleanupErr != nil {
+			return pkgMeta, cleanupErr
+		}
 	}
 
 	pkgMeta.InstallPath = targetDirectory
 
+	executable, interpreterErr := findInterpreter(targetDirectory)
+
+	if interpreterErr != nil {
+		return pkgMeta, interpreterErr
+	}
+
+	pkgMeta.Executable = executable
+
+	logger.InfoLogger.Println("Verifying extension modules")
+	log.Stage("Verify")
+
+	if verifyErr := verifyBuiltModules(pkgMeta.Executable, log); verifyErr != nil {
+		// A build missing required modules must not be selectable (see: use).
+		if cleanupErr := os.RemoveAll(targetDirectory); cleanupErr != nil {
+			return pkgMeta, cleanupErr
+		}
+
+		return pkgMeta, verifyErr
+	}
+
 	logger.InfoLogger.Printf("✅ Installed Python %s at %s (%s)\n", pkgMeta.Version, pkgMeta.InstallPath, time.Since(start))
 	return pkgMeta, nil
 }
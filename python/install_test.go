@@ -0,0 +1,257 @@
+package python
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	download "v/download"
+	exec "v/exec"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func setupDownloadTest(t *testing.T) *buildLog {
+	testutils.CaptureOutput(t)
+
+	os.MkdirAll(state.GetStatePath("cache"), 0750)
+	log, _ := newBuildLog("1.2.3")
+	t.Cleanup(func() { log.Close() })
+
+	return log
+}
+
+func TestGetArchiveVersion(t *testing.T) {
+	version, err := getArchiveVersion("./archives/Python-3.11.4.tgz")
+
+	if err != nil || version != "3.11.4" {
+		t.Errorf("Expected 3.11.4, got %s (%v)", version, err)
+	}
+
+	if _, err := getArchiveVersion("./archives/cpython-main.zip"); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+// Packs a source tree under <root> into the archive <name> in <directory>.
+func writeSourceArchive(t *testing.T, directory string, name string, root string) string {
+	sourceRoot := t.TempDir()
+	os.MkdirAll(path.Join(sourceRoot, root), 0750)
+	os.WriteFile(path.Join(sourceRoot, root, "configure"), []byte("#!/bin/sh\n"), 0750)
+
+	archivePath := path.Join(directory, name)
+
+	if _, err := exec.RunCommand([]string{"tar", "-czf", archivePath, root}, sourceRoot); err != nil {
+		t.Fatalf("Could not create archive: %v", err)
+	}
+
+	return archivePath
+}
+
+func TestDownloadSourceFromArchiveSeedsCache(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	archiveDir := path.Join(t.TempDir(), "release #1")
+	os.MkdirAll(archiveDir, 0750)
+	archivePath := writeSourceArchive(t, archiveDir, "Python-1.2.3.tgz", "Python-1.2.3")
+	expected, _ := os.ReadFile(archivePath)
+
+	pkgMeta, err := downloadSource("1.2.3", InstallOptions{FromArchive: archivePath}, log)
+
+	if err != nil || pkgMeta.SourceURL != download.FileURL(archivePath) {
+		t.Errorf("Unexpected result: %v (%v)", pkgMeta, err)
+	}
+
+	content, _ := os.ReadFile(state.GetStatePath("cache", "Python-1.2.3.tgz"))
+
+	if !bytes.Equal(content, expected) {
+		t.Errorf("Expected archive to be copied to the cache.")
+	}
+}
+
+func TestDownloadSourceRejectsMismatchedArchive(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	archivePath := writeSourceArchive(t, t.TempDir(), "custom.tgz", "Python-1.2.4")
+
+	if _, err := downloadSource("1.2.3", InstallOptions{FromArchive: archivePath}, log); err == nil || !strings.Contains(err.Error(), "expected Python-1.2.3") {
+		t.Errorf("Expected the mismatch to be reported, got %v", err)
+	}
+
+	if fileExists(state.GetStatePath("cache", "Python-1.2.3.tgz")) {
+		t.Errorf("Did not expect the archive to be cached.")
+	}
+}
+
+func TestDownloadSourceOfflineUsesArchiveDir(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	archiveDir := t.TempDir()
+	os.WriteFile(path.Join(archiveDir, "Python-1.2.3.tgz"), []byte("archive"), 0750)
+
+	_, err := downloadSource("1.2.3", InstallOptions{Offline: true, ArchiveDir: archiveDir}, log)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(state.GetStatePath("cache", "Python-1.2.3.tgz")); err != nil {
+		t.Errorf("Expected archive to be copied to the cache.")
+	}
+}
+
+func TestDownloadSourceOfflineUsesCacheDespiteNoCache(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	os.WriteFile(state.GetStatePath("cache", "Python-1.2.3.tgz"), []byte("archive"), 0750)
+
+	if _, err := downloadSource("1.2.3", InstallOptions{Offline: true, NoCache: true}, log); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestDownloadSourceOfflineFailsIfArchiveNotAvailable(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	_, err := downloadSource("1.2.3", InstallOptions{Offline: true, ArchiveDir: t.TempDir()}, log)
+
+	if err == nil || !strings.Contains(err.Error(), "Python-1.2.3.tgz not available offline") {
+		t.Errorf("Expected offline error, got %v", err)
+	}
+}
+
+func TestDownloadSourceOfflineUsesLocalMirror(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	mirrorPath := t.TempDir()
+	os.MkdirAll(path.Join(mirrorPath, "1.2.3"), 0750)
+	os.WriteFile(path.Join(mirrorPath, "1.2.3", "Python-1.2.3.tgz"), []byte("archive"), 0750)
+	t.Setenv(mirrorsEnvVar, "https://www.python.org/ftp/python,file://"+mirrorPath)
+
+	pkgMeta, err := downloadSource("1.2.3", InstallOptions{Offline: true}, log)
+
+	if err != nil || pkgMeta.SourceURL != "file://"+path.Join(mirrorPath, "1.2.3", "Python-1.2.3.tgz") {
+		t.Errorf("Unexpected result: %v (%v)", pkgMeta, err)
+	}
+}
+
+// Serves the first 4 bytes of a 7 bytes archive, interrupting the download.
+func serveTruncatedArchive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Length", "7")
+	w.Write([]byte("arch"))
+}
+
+func TestDownloadSourceKeepsPartialIfOnlyMirrorFails(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	interrupted := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if interrupted {
+			serveTruncatedArchive(w, r)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(mirrorsEnvVar, server.URL)
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"download": {"retries": 0}}`), 0750)
+	partialPath := state.GetStatePath("cache", "Python-1.2.3.tgz.partial")
+
+	for _, interrupted = range []bool{true, false} {
+		if _, err := downloadSource("1.2.3", InstallOptions{}, log); err == nil {
+			t.Errorf("Expected the download to fail.")
+		}
+	}
+
+	if content, _ := os.ReadFile(partialPath); string(content) != "arch" {
+		t.Errorf("Expected the partial download to be kept for the next attempt.")
+	}
+}
+
+func TestDownloadSourceDoesNotResumePartialFromAnotherMirror(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	firstAvailable := false
+	var rangeRequested bool
+
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !firstAvailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		rangeRequested = r.Header.Get("Range") != ""
+		w.Write([]byte("archive"))
+	}))
+	defer first.Close()
+
+	second := httptest.NewServer(http.HandlerFunc(serveTruncatedArchive))
+	defer second.Close()
+
+	t.Setenv(mirrorsEnvVar, first.URL+","+second.URL)
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"download": {"retries": 0}}`), 0750)
+
+	// The second mirror leaves a partial download behind.
+	if _, err := downloadSource("1.2.3", InstallOptions{}, log); err == nil {
+		t.Fatalf("Expected the download to fail.")
+	}
+
+	firstAvailable = true
+
+	if _, err := downloadSource("1.2.3", InstallOptions{}, log); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if rangeRequested {
+		t.Errorf("Did not expect the first mirror to resume the second mirror's partial download.")
+	}
+
+	if content, _ := os.ReadFile(state.GetStatePath("cache", "Python-1.2.3.tgz")); string(content) != "archive" {
+		t.Errorf("Unexpected archive content: %s", content)
+	}
+}
+
+func TestFailedReinstallKeepsPreviousInstall(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	setupDownloadTest(t)
+
+	installPath := state.GetStatePath("runtimes", "python", "dev")
+	os.MkdirAll(path.Join(installPath, "bin"), 0750)
+	os.WriteFile(path.Join(installPath, "bin", "python1.2"), []byte("#!/bin/sh\n"), 0750)
+	WriteManifest("dev", Manifest{Version: "dev", Executable: "bin/python1.2"})
+
+	// Installs an interpreter that cannot import anything, failing verification.
+	sourceDir := t.TempDir()
+	configure := "#!/bin/sh\nprefix=${1#--prefix=}\nprintf 'altinstall:\\n\\tmkdir -p %s/bin\\n\\tprintf \"#!/bin/sh\\\\nexit 1\\\\n\" > %s/bin/python1.3\\n\\tchmod +x %s/bin/python1.3\\n' \"$prefix\" \"$prefix\" \"$prefix\" > Makefile\n"
+	os.WriteFile(path.Join(sourceDir, "configure"), []byte(configure), 0750)
+
+	options := InstallOptions{SourceDir: sourceDir, Build: BuildOptions{Jobs: 1, DisablePGO: true}, SkipDefaultPackages: true}
+
+	if err := InstallPythonDistribution("dev", options); err == nil {
+		t.Fatalf("Expected the install to fail verification.")
+	}
+
+	if _, err := os.Stat(path.Join(installPath, "bin", "python1.3")); err == nil {
+		t.Errorf("Did not expect the failed build to be kept.")
+	}
+
+	if manifest, err := ReadManifest("dev"); err != nil || manifest.Executable != "bin/python1.2" {
+		t.Errorf("Expected the previous install to be restored, got %v (%v)", manifest, err)
+	}
+
+	if installed, _ := ListInstalledVersions(); len(installed) != 1 || fileExists(getPreviousInstallPath("dev")) {
+		t.Errorf("Expected only the restored install, got %v", installed)
+	}
+}
@@ -0,0 +1,112 @@
+package python
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"runtime"
+	"time"
+	state "v/state"
+)
+
+const manifestFilename = ".v-manifest.json"
+
+// Version of v writing manifests, set by the entrypoint.
+var ToolVersion = "dev"
+
+// Metadata written alongside each installed runtime, describing how
+// it was built. Installs without a manifest are considered incomplete.
+type Manifest struct {
+	Version       string `json:"version"`
+	SourceURL     string `json:"sourceUrl"`
+	ArchiveDigest string `json:"archiveDigest,omitempty"`
+	GitRef        string `json:"gitRef,omitempty"`
+	GitCommit     string `json:"gitCommit,omitempty"`
+	// Prefix of interpreters installed outside of v (see: `v python link`).
+	Prefix string `json:"prefix,omitempty"`
+	// Version manager the install was imported from (see: `v import`).
+	ImportedFrom string `json:"importedFrom,omitempty"`
+	// Path to the interpreter, relative to the install directory (or Prefix).
+	Executable   string         `json:"executable"`
+	Patches      []AppliedPatch `json:"patches,omitempty"`
+	BuildOptions BuildOptions   `json:"buildOptions"`
+	// Outcome of installing the default packages, if any were listed.
+	DefaultPackages *DefaultPackagesResult `json:"defaultPackages,omitempty"`
+	VVersion        string                 `json:"vVersion"`
+	InstalledAt     time.Time              `json:"installedAt"`
+	BuildDuration   time.Duration          `json:"buildDuration"`
+	LogPath         string                 `json:"logPath"`
+	Host            HostInfo               `json:"host"`
+}
+
+// Describes the machine an install was built on.
+type HostInfo struct {
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Hostname string `json:"hostname"`
+}
+
+func getHostInfo() HostInfo {
+	hostname, _ := os.Hostname()
+
+	return HostInfo{OS: runtime.GOOS, Arch: runtime.GOARCH, Hostname: hostname}
+}
+
+// Returns the hex-encoded SHA256 digest of the file at <filePath>.
+func getFileDigest(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func getManifestPath(version string) string {
+	return state.GetStatePath("runtimes", "python", version, manifestFilename)
+}
+
+func WriteManifest(version string, manifest Manifest) error {
+	d, err := json.MarshalIndent(manifest, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(getManifestPath(version), d, 0640)
+}
+
+// ReadManifest returns the manifest of the installed version <version>.
+// An error wrapping os.ErrNotExist is returned if the install has no manifest.
+func ReadManifest(version string) (Manifest, error) {
+	manifest := Manifest{}
+
+	c, err := os.ReadFile(getManifestPath(version))
+
+	if err != nil {
+		return manifest, err
+	}
+
+	err = json.Unmarshal(c, &manifest)
+
+	return manifest, err
+}
+
+// IsCompleteInstall returns whether the installed version <version> was
+// completely installed by v, as opposed to a stray directory left behind.
+func IsCompleteInstall(version string) bool {
+	_, err := os.Stat(getManifestPath(version))
+
+	return err == nil
+}
@@ -0,0 +1,59 @@
+package python
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestWriteManifestRoundTrip(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+
+	manifest := Manifest{
+		Version:       "1.2.3",
+		ArchiveDigest: "sha256:abc",
+		BuildOptions:  BuildOptions{Jobs: 4, LTO: true},
+		BuildDuration: 2 * time.Minute,
+	}
+
+	if err := WriteManifest("1.2.3", manifest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	readManifest, err := ReadManifest("1.2.3")
+
+	if err != nil || readManifest.ArchiveDigest != "sha256:abc" || readManifest.BuildOptions.Jobs != 4 || readManifest.BuildDuration != 2*time.Minute {
+		t.Errorf("Unexpected manifest: %v (%v)", readManifest, err)
+	}
+
+	if !IsCompleteInstall("1.2.3") {
+		t.Errorf("Expected install to be complete.")
+	}
+}
+
+func TestIsCompleteInstallWithoutManifest(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "1.2.3"), 0750)
+
+	if IsCompleteInstall("1.2.3") {
+		t.Errorf("Expected install without manifest to be incomplete.")
+	}
+}
+
+func TestGetFileDigest(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "archive.tgz")
+	os.WriteFile(filePath, []byte("hello"), 0750)
+
+	digest, err := getFileDigest(filePath)
+
+	expected := "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if err != nil || digest != expected {
+		t.Errorf("Expected %s, got %s (%v)", expected, digest, err)
+	}
+}
@@ -0,0 +1,115 @@
+package python
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	download "v/download"
+	logger "v/logger"
+)
+
+var pythonReleasesBaseURL = "https://www.python.org/ftp/python"
+
+// Environment variable overriding the configured mirrors, as a
+// comma-separated list of base URLs.
+const mirrorsEnvVar = "V_PYTHON_MIRRORS"
+
+// Returns the base URLs to fetch releases from, in order of preference.
+// Mirrors are expected to follow the same layout as python.org
+// (<base>/<version>/Python-<version>.tgz). They are read from the
+// V_PYTHON_MIRRORS environment variable, the python.mirrors configuration
+// or default to python.org.
+func getMirrors() ([]string, error) {
+	if envMirrors, found := os.LookupEnv(mirrorsEnvVar); found && strings.TrimSpace(envMirrors) != "" {
+		mirrors := []string{}
+
+		for _, mirror := range strings.Split(envMirrors, ",") {
+			if trimmed := strings.TrimSpace(mirror); trimmed != "" {
+				mirrors = append(mirrors, trimmed)
+			}
+		}
+
+		return mirrors, nil
+	}
+
+	config, err := ReadConfig()
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	if len(config.Mirrors) != 0 {
+		return config.Mirrors, nil
+	}
+
+	return []string{pythonReleasesBaseURL}, nil
+}
+
+// Returns the base URLs to fetch releases of <implementation> (pypy or graalpy)
+// from, in order of preference. Mirrors are expected to follow the same layout
+// as the upstream release URLs (see: ImplementationVersion.archiveURL). They
+// are read from the python.implementationMirrors configuration or default to
+// upstream.
+func getImplementationMirrors(implementation string) ([]string, error) {
+	config, err := ReadConfig()
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	if mirrors := config.ImplementationMirrors[implementation]; len(mirrors) != 0 {
+		return mirrors, nil
+	}
+
+	if implementation == "pypy" {
+		return []string{pypyReleasesBaseURL}, nil
+	}
+
+	return []string{graalpyReleasesBaseURL}, nil
+}
+
+// Calls <fetch> with each CPython mirror's base URL in order until one
+// succeeds (see: tryEachMirror).
+func tryMirrors(offline bool, fetch func(baseURL string) error) error {
+	mirrors, err := getMirrors()
+
+	if err != nil {
+		return err
+	}
+
+	return tryEachMirror(mirrors, offline, fetch)
+}
+
+// Calls <fetch> with each of <mirrors> in order until one succeeds. If all
+// mirrors fail, an error listing each failure is returned. In offline mode,
+// only local (file://) mirrors are tried.
+func tryEachMirror(mirrors []string, offline bool, fetch func(baseURL string) error) error {
+	if offline {
+		mirrors = slices.DeleteFunc(mirrors, func(mirror string) bool {
+			return !download.IsLocalURL(mirror)
+		})
+
+		if len(mirrors) == 0 {
+			return errors.New("No local (file://) mirror configured")
+		}
+	}
+
+	failures := []string{}
+
+	for index, mirror := range mirrors {
+		err := fetch(mirror)
+
+		if err == nil {
+			return nil
+		}
+
+		failures = append(failures, mirror+": "+err.Error())
+
+		if index < len(mirrors)-1 {
+			logger.InfoLogger.Println(logger.Yellow("Failed to fetch from " + mirror + ", trying next mirror."))
+		}
+	}
+
+	return errors.New("All mirrors failed:\n  " + strings.Join(failures, "\n  "))
+}
@@ -0,0 +1,109 @@
+package python
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestGetMirrorsDefaultsToPythonOrg(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	mirrors, err := getMirrors()
+
+	if err != nil || !slices.Equal(mirrors, []string{pythonReleasesBaseURL}) {
+		t.Errorf("Unexpected mirrors: %v (%v)", mirrors, err)
+	}
+}
+
+func TestGetMirrorsUsesConfiguration(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"python": {"mirrors": ["https://a.example.com", "https://b.example.com"]}}`), 0750)
+
+	mirrors, err := getMirrors()
+
+	if err != nil || !slices.Equal(mirrors, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Errorf("Unexpected mirrors: %v (%v)", mirrors, err)
+	}
+}
+
+func TestGetMirrorsEnvironmentOverridesConfiguration(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"python": {"mirrors": ["https://a.example.com"]}}`), 0750)
+	t.Setenv(mirrorsEnvVar, "https://c.example.com, https://d.example.com")
+
+	mirrors, err := getMirrors()
+
+	if err != nil || !slices.Equal(mirrors, []string{"https://c.example.com", "https://d.example.com"}) {
+		t.Errorf("Unexpected mirrors: %v (%v)", mirrors, err)
+	}
+}
+
+func TestTryMirrorsFallsBackInOrder(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	t.Setenv(mirrorsEnvVar, "https://a.example.com,https://b.example.com,https://c.example.com")
+
+	tried := []string{}
+	err := tryMirrors(false, func(baseURL string) error {
+		tried = append(tried, baseURL)
+
+		if baseURL == "https://a.example.com" {
+			return errors.New("unreachable")
+		}
+
+		return nil
+	})
+
+	if err != nil || !slices.Equal(tried, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Errorf("Unexpected mirrors tried: %v (%v)", tried, err)
+	}
+}
+
+func TestTryMirrorsReturnsErrorIfAllFail(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	t.Setenv(mirrorsEnvVar, "https://a.example.com,https://b.example.com")
+
+	err := tryMirrors(false, func(baseURL string) error {
+		return errors.New("unreachable")
+	})
+
+	if err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
+
+func TestTryMirrorsOfflineOnlyUsesLocalMirrors(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	t.Setenv(mirrorsEnvVar, "https://a.example.com,file:///srv/python")
+
+	tried := []string{}
+	err := tryMirrors(true, func(baseURL string) error {
+		tried = append(tried, baseURL)
+		return nil
+	})
+
+	if err != nil || !slices.Equal(tried, []string{"file:///srv/python"}) {
+		t.Errorf("Unexpected mirrors tried: %v (%v)", tried, err)
+	}
+}
+
+func TestTryMirrorsOfflineWithoutLocalMirrors(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	err := tryMirrors(true, func(baseURL string) error {
+		t.Errorf("Did not expect %s to be tried.", baseURL)
+		return nil
+	})
+
+	if err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
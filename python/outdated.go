@@ -0,0 +1,167 @@
+package python
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+	cli "v/cli"
+	logger "v/logger"
+	state "v/state"
+)
+
+// End-of-life dates of CPython minor versions (see: https://devguide.python.org/versions/).
+// Entries can be added or overridden via the python.endOfLife configuration.
+var endOfLifeDates = map[string]string{
+	"2.7":  "2020-01-01",
+	"3.5":  "2020-09-30",
+	"3.6":  "2021-12-23",
+	"3.7":  "2023-06-27",
+	"3.8":  "2024-10-07",
+	"3.9":  "2025-10-31",
+	"3.10": "2026-10-31",
+	"3.11": "2027-10-31",
+	"3.12": "2028-10-31",
+	"3.13": "2029-10-31",
+	"3.14": "2030-10-31",
+}
+
+// Status of an installed minor version line.
+type OutdatedReport struct {
+	Minor     string   `json:"minor"`
+	Installed []string `json:"installed"`
+	// Latest release of the line available for download, if known.
+	Latest   string `json:"latest,omitempty"`
+	Outdated bool   `json:"outdated"`
+	// End-of-life date of the line (YYYY-MM-DD), if known.
+	EndOfLife   string `json:"endOfLife,omitempty"`
+	IsEndOfLife bool   `json:"isEndOfLife"`
+}
+
+// Returns the end-of-life dates, layering the configured ones over the
+// embedded table.
+func getEndOfLifeDates() (map[string]string, error) {
+	config, err := ReadConfig()
+
+	if err != nil {
+		return map[string]string{}, err
+	}
+
+	dates := maps.Clone(endOfLifeDates)
+	maps.Copy(dates, config.EndOfLife)
+
+	return dates, nil
+}
+
+// Builds one report per minor line of the <installed> releases, comparing
+// them to the <remote> releases. Installs that are not CPython releases
+// (i.e. development builds) are ignored.
+func buildOutdatedReports(installed []string, remote []string, endOfLife map[string]string, now time.Time) []OutdatedReport {
+	reports := []OutdatedReport{}
+
+	for _, version := range installed {
+		if ValidateVersion(version) != nil {
+			continue
+		}
+
+		minor := VersionStringToStruct(version).MajorMinor()
+		index := slices.IndexFunc(reports, func(report OutdatedReport) bool { return report.Minor == minor })
+
+		if index == -1 {
+			reports = append(reports, OutdatedReport{Minor: minor, Installed: []string{}})
+			index = len(reports) - 1
+		}
+
+		reports[index].Installed = append(reports[index].Installed, version)
+	}
+
+	for index := range reports {
+		report := &reports[index]
+		slices.SortFunc(report.Installed, CompareVersions)
+
+		for _, remoteVersion := range remote {
+			if ValidateVersion(remoteVersion) == nil && VersionStringToStruct(remoteVersion).MajorMinor() == report.Minor && (report.Latest == "" || CompareVersions(remoteVersion, report.Latest) > 0) {
+				report.Latest = remoteVersion
+			}
+		}
+
+		report.Outdated = report.Latest != "" && CompareVersions(report.Installed[len(report.Installed)-1], report.Latest) < 0
+
+		if date, found := endOfLife[report.Minor]; found {
+			report.EndOfLife = date
+
+			if parsed, err := time.Parse(time.DateOnly, date); err == nil {
+				report.IsEndOfLife = !now.Before(parsed)
+			}
+		}
+	}
+
+	slices.SortFunc(reports, func(a, b OutdatedReport) int {
+		return CompareVersions(a.Minor, b.Minor)
+	})
+
+	return reports
+}
+
+// Outdated (called via `v python outdated`) reports, per installed minor
+// version line, whether a newer patch release is available and whether the
+// line reached its end of life. The report is printed as JSON with --raw.
+func outdated(args []string, flags cli.Flags, currentState state.State) error {
+	installed, err := ListInstalledVersions()
+
+	if err != nil {
+		return err
+	}
+
+	offline, err := isOffline(flags)
+
+	if err != nil {
+		return err
+	}
+
+	remote, err := ListRemoteVersions(offline)
+
+	if err != nil {
+		return err
+	}
+
+	endOfLife, err := getEndOfLifeDates()
+
+	if err != nil {
+		return err
+	}
+
+	reports := buildOutdatedReports(installed, remote, endOfLife, time.Now())
+
+	if flags.RawOutput {
+		d, _ := json.MarshalIndent(reports, "", "  ")
+		logger.InfoLogger.Println(string(d))
+		return nil
+	}
+
+	if len(reports) == 0 {
+		logger.InfoLogger.Println("No versions installed!")
+		return nil
+	}
+
+	for _, report := range reports {
+		latestInstalled := report.Installed[len(report.Installed)-1]
+		line := logger.Bold(report.Minor) + ": " + latestInstalled
+
+		if report.Outdated {
+			line += logger.Yellow(" -> " + report.Latest + " available")
+		} else if report.Latest != "" {
+			line += " (latest)"
+		}
+
+		if report.IsEndOfLife {
+			line += logger.Yellow(" (end-of-life since " + report.EndOfLife + ")")
+		} else if report.EndOfLife != "" {
+			line += " (supported until " + report.EndOfLife + ")"
+		}
+
+		logger.InfoLogger.Println(line)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package python
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+	"time"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestBuildOutdatedReportsGroupsByMinorVersion(t *testing.T) {
+	now, _ := time.Parse(time.DateOnly, "2025-01-01")
+
+	reports := buildOutdatedReports(
+		[]string{"3.11.7", "3.8.10", "3.11.4", "main-20261018"},
+		[]string{"3.8.18", "3.8.20", "3.11.7", "3.12.0"},
+		map[string]string{"3.8": "2024-10-07", "3.11": "2027-10-31"},
+		now,
+	)
+
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 reports, got %v", reports)
+	}
+
+	if report := reports[0]; report.Minor != "3.8" || report.Latest != "3.8.20" || !report.Outdated || !report.IsEndOfLife {
+		t.Errorf("Unexpected report for 3.8: %v", report)
+	}
+
+	if report := reports[1]; report.Minor != "3.11" || !slices.Equal(report.Installed, []string{"3.11.4", "3.11.7"}) || report.Outdated || report.IsEndOfLife {
+		t.Errorf("Unexpected report for 3.11: %v", report)
+	}
+}
+
+func TestGetEndOfLifeDatesUsesConfiguredDates(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"python": {"endOfLife": {"3.8": "2030-01-01"}}}`), 0640)
+
+	dates, err := getEndOfLifeDates()
+
+	if err != nil || dates["3.8"] != "2030-01-01" || dates["3.12"] != endOfLifeDates["3.12"] {
+		t.Errorf("Unexpected dates: %v (%v)", dates, err)
+	}
+}
+
+func TestOutdatedOutputsJSONIfRawOutput(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	setupLocalMirror(t, []string{"3.11.7", "3.11.9"})
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "3.11.7"), 0750)
+
+	if err := outdated([]string{"outdated"}, cli.Flags{RawOutput: true, Offline: true}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reports := []OutdatedReport{}
+
+	if err := json.Unmarshal(out.Bytes(), &reports); err != nil || len(reports) != 1 || reports[0].Latest != "3.11.9" || !reports[0].Outdated {
+		t.Errorf("Unexpected output: %s (%v)", out.String(), err)
+	}
+}
@@ -0,0 +1,105 @@
+package python
+
+import (
+	"errors"
+	"os"
+	"strings"
+	download "v/download"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+// File under the state root listing the packages installed after each
+// Python install, one requirement per line.
+const defaultPackagesFilename = "default-python-packages"
+
+// Outcome of installing the default packages, recorded in the install manifest.
+type DefaultPackagesResult struct {
+	Requirements []string `json:"requirements"`
+	Installed    bool     `json:"installed"`
+	Error        string   `json:"error,omitempty"`
+	// Reason the packages were not installed, if skipped (i.e. offline).
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// Returns the requirements listed in the default packages file. Empty lines
+// and comments (#) are ignored. No requirements are returned if the file does
+// not exist.
+func readDefaultPackages() ([]string, error) {
+	content, err := os.ReadFile(state.GetStatePath(defaultPackagesFilename))
+
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	requirements := []string{}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		requirement, _, _ := strings.Cut(line, "#")
+
+		if requirement = strings.TrimSpace(requirement); requirement != "" {
+			requirements = append(requirements, requirement)
+		}
+	}
+
+	return requirements, nil
+}
+
+// Installs (or upgrades) the default packages with the interpreter at
+// <interpreterPath>. Failures do not fail the install: they are reported and
+// recorded in the returned result. Nil is returned if there are no default packages.
+//
+// In offline mode, packages are only installed from the wheels found in the
+// archive directory, and skipped if there is none.
+func installDefaultPackages(interpreterPath string, options InstallOptions, log *buildLog) *DefaultPackagesResult {
+	requirements, err := readDefaultPackages()
+
+	if err == nil && len(requirements) == 0 {
+		return nil
+	}
+
+	result := &DefaultPackagesResult{Requirements: requirements}
+	command := []string{interpreterPath, "-m", "pip", "install", "--upgrade"}
+
+	if options.Offline && options.ArchiveDir == "" {
+		logger.InfoLogger.Println(logger.Yellow("Offline mode: skipping the default packages. Set python.archiveDir to install them from local wheels."))
+		result.Skipped = "offline"
+		return result
+	}
+
+	if options.Offline {
+		command = append(command, "--no-index", "--find-links", options.ArchiveDir)
+	}
+
+	var env []string
+
+	if err == nil {
+		env, err = download.SubprocessEnv()
+	}
+
+	if err == nil {
+		logger.InfoLogger.Println("Installing default packages: " + strings.Join(requirements, ", "))
+		log.Stage("Default packages")
+
+		_, err = exec.RunCommandWithOptions(append(command, requirements...), state.GetStatePath(), exec.CommandOptions{Env: env, Log: log})
+
+		if err != nil {
+			err = errors.New("pip install failed (" + err.Error() + ")")
+		}
+	}
+
+	if err != nil {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: Failed to install the default packages: " + err.Error() + ". See: " + log.Path))
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Installed = true
+
+	return result
+}
@@ -0,0 +1,87 @@
+package python
+
+import (
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestReadDefaultPackagesSkipsCommentsAndEmptyLines(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(state.GetStatePath(defaultPackagesFilename), []byte("# Tooling\npip\n\nwheel  # for builds\nvirtualenv>=20\n"), 0640)
+
+	requirements, err := readDefaultPackages()
+
+	if err != nil || !slices.Equal(requirements, []string{"pip", "wheel", "virtualenv>=20"}) {
+		t.Errorf("Unexpected requirements: %v (%v)", requirements, err)
+	}
+}
+
+func TestInstallDefaultPackagesSkipsIfNoFile(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	if result := installDefaultPackages("/nonexistent/python", InstallOptions{}, log); result != nil {
+		t.Errorf("Expected no result, got %v", result)
+	}
+}
+
+func TestInstallDefaultPackagesRecordsOutcome(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	os.WriteFile(state.GetStatePath(defaultPackagesFilename), []byte("pip\nwheel\n"), 0640)
+
+	argsPath := path.Join(t.TempDir(), "args")
+	mockInterpreter := path.Join(t.TempDir(), "python")
+	os.WriteFile(mockInterpreter, []byte("#!/bin/sh\necho \"$@\" > "+argsPath+"\n"), 0777)
+
+	result := installDefaultPackages(mockInterpreter, InstallOptions{}, log)
+
+	if result == nil || !result.Installed || !slices.Equal(result.Requirements, []string{"pip", "wheel"}) {
+		t.Errorf("Unexpected result: %v", result)
+	}
+
+	if args, _ := os.ReadFile(argsPath); strings.TrimSpace(string(args)) != "-m pip install --upgrade pip wheel" {
+		t.Errorf("Unexpected pip call: %s", args)
+	}
+
+	failingInterpreter := path.Join(t.TempDir(), "python")
+	os.WriteFile(failingInterpreter, []byte("#!/bin/sh\nexit 1\n"), 0777)
+
+	if result := installDefaultPackages(failingInterpreter, InstallOptions{}, log); result == nil || result.Installed || result.Error == "" {
+		t.Errorf("Expected the failure to be recorded, got %v", result)
+	}
+}
+
+func TestInstallDefaultPackagesOffline(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	os.WriteFile(state.GetStatePath(defaultPackagesFilename), []byte("pip\n"), 0640)
+
+	argsPath := path.Join(t.TempDir(), "args")
+	mockInterpreter := path.Join(t.TempDir(), "python")
+	os.WriteFile(mockInterpreter, []byte("#!/bin/sh\necho \"$@\" > "+argsPath+"\n"), 0777)
+
+	if result := installDefaultPackages(mockInterpreter, InstallOptions{Offline: true}, log); result == nil || result.Installed || result.Skipped != "offline" {
+		t.Errorf("Expected the packages to be skipped, got %v", result)
+	}
+
+	if _, err := os.Stat(argsPath); !os.IsNotExist(err) {
+		t.Errorf("Did not expect pip to run without an archive directory.")
+	}
+
+	if result := installDefaultPackages(mockInterpreter, InstallOptions{Offline: true, ArchiveDir: "/srv/wheels"}, log); result == nil || !result.Installed {
+		t.Errorf("Unexpected result: %v", result)
+	}
+
+	if args, _ := os.ReadFile(argsPath); strings.TrimSpace(string(args)) != "-m pip install --upgrade --no-index --find-links /srv/wheels pip" {
+		t.Errorf("Unexpected pip call: %s", args)
+	}
+}
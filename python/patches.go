@@ -0,0 +1,104 @@
+package python
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Patch applied to the source tree before building, recorded in the
+// install manifest.
+type AppliedPatch struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Digest string `json:"digest"`
+}
+
+// Returns the directories patches are picked up from for <version>:
+// patches/python/<major>.<minor> and patches/python/<version> under
+// the state root.
+func getPatchDirectories(version string) []string {
+	directories := []string{}
+
+	if ValidateVersion(version) == nil {
+		directories = append(directories, state.GetStatePath("patches", "python", VersionStringToStruct(version).MajorMinor()))
+	}
+
+	return append(directories, state.GetStatePath("patches", "python", version))
+}
+
+// Returns the patches to apply when building <version>: the .patch files
+// found in its patch directories (in name order), followed by <extra> patches
+// passed by the user.
+func collectPatches(version string, extra []string) ([]string, error) {
+	patches := []string{}
+
+	for _, directory := range getPatchDirectories(version) {
+		entries, err := os.ReadDir(directory)
+
+		if err != nil && !os.IsNotExist(err) {
+			return []string{}, err
+		}
+
+		names := []string{}
+
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".patch") {
+				names = append(names, entry.Name())
+			}
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			patches = append(patches, path.Join(directory, name))
+		}
+	}
+
+	for _, patchPath := range extra {
+		absolutePatchPath, err := filepath.Abs(patchPath)
+
+		if err != nil {
+			return []string{}, err
+		}
+
+		if _, err := os.Stat(absolutePatchPath); err != nil {
+			return []string{}, errors.New("Patch not found: " + patchPath)
+		}
+
+		patches = append(patches, absolutePatchPath)
+	}
+
+	return patches, nil
+}
+
+// Applies <patches> in order to the source tree at <sourceRoot>. Patches
+// are expected to be relative to the root of the tree (as produced by
+// `git diff` or `diff -ru a/ b/`).
+func applyPatches(sourceRoot string, patches []string, log *buildLog) ([]AppliedPatch, error) {
+	applied := []AppliedPatch{}
+
+	for _, patchPath := range patches {
+		logger.InfoLogger.Println("Applying " + path.Base(patchPath))
+
+		if _, err := exec.RunCommandWithOptions([]string{"patch", "-p1", "--forward", "--batch", "-i", patchPath}, sourceRoot, exec.CommandOptions{Log: log}); err != nil {
+			return applied, errors.New("Failed to apply patch " + patchPath)
+		}
+
+		digest, err := getFileDigest(patchPath)
+
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, AppliedPatch{Name: path.Base(patchPath), Path: patchPath, Digest: digest})
+	}
+
+	return applied, nil
+}
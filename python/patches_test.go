@@ -0,0 +1,96 @@
+package python
+
+import (
+	"os"
+	"path"
+	"testing"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+func TestCollectPatchesOrdersDirectoryPatchesBeforeExtraPatches(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	minorDirectory := state.GetStatePath("patches", "python", "3.8")
+	versionDirectory := state.GetStatePath("patches", "python", "3.8.10")
+	os.MkdirAll(minorDirectory, 0750)
+	os.MkdirAll(versionDirectory, 0750)
+	os.WriteFile(path.Join(minorDirectory, "02-b.patch"), []byte(""), 0640)
+	os.WriteFile(path.Join(minorDirectory, "01-a.patch"), []byte(""), 0640)
+	os.WriteFile(path.Join(minorDirectory, "README"), []byte(""), 0640)
+	os.WriteFile(path.Join(versionDirectory, "00-c.patch"), []byte(""), 0640)
+
+	extraPatch := path.Join(t.TempDir(), "extra.patch")
+	os.WriteFile(extraPatch, []byte(""), 0640)
+
+	patches, err := collectPatches("3.8.10", []string{extraPatch})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		path.Join(minorDirectory, "01-a.patch"),
+		path.Join(minorDirectory, "02-b.patch"),
+		path.Join(versionDirectory, "00-c.patch"),
+		extraPatch,
+	}
+
+	if len(patches) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, patches)
+	}
+
+	for index := range expected {
+		if patches[index] != expected[index] {
+			t.Errorf("Expected %s at position %d, got %s", expected[index], index, patches[index])
+		}
+	}
+}
+
+func TestCollectPatchesFailsIfExtraPatchMissing(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	if _, err := collectPatches("3.8.10", []string{path.Join(t.TempDir(), "missing.patch")}); err == nil {
+		t.Errorf("Expected an error for a missing patch.")
+	}
+}
+
+func TestApplyPatchesModifiesSourceTree(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	sourceRoot := t.TempDir()
+	os.WriteFile(path.Join(sourceRoot, "configure"), []byte("old\n"), 0750)
+
+	patchPath := path.Join(t.TempDir(), "fix.patch")
+	os.WriteFile(patchPath, []byte("--- a/configure\n+++ b/configure\n@@ -1 +1 @@\n-old\n+new\n"), 0640)
+
+	applied, err := applyPatches(sourceRoot, []string{patchPath}, log)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if content, _ := os.ReadFile(path.Join(sourceRoot, "configure")); string(content) != "new\n" {
+		t.Errorf("Expected patched content, got %q", string(content))
+	}
+
+	if len(applied) != 1 || applied[0].Name != "fix.patch" || applied[0].Digest == "" {
+		t.Errorf("Expected fix.patch to be recorded with its digest, got %v", applied)
+	}
+}
+
+func TestApplyPatchesFailsIfPatchDoesNotApply(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	log := setupDownloadTest(t)
+
+	sourceRoot := t.TempDir()
+	os.WriteFile(path.Join(sourceRoot, "configure"), []byte("other\n"), 0750)
+
+	patchPath := path.Join(t.TempDir(), "fix.patch")
+	os.WriteFile(patchPath, []byte("--- a/configure\n+++ b/configure\n@@ -1 +1 @@\n-old\n+new\n"), 0640)
+
+	if _, err := applyPatches(sourceRoot, []string{patchPath}, log); err == nil {
+		t.Errorf("Expected an error for a patch that does not apply.")
+	}
+}
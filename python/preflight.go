@@ -0,0 +1,211 @@
+package python
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	vexec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Standard library extension modules that are only built if their
+// development headers are available when configuring the build.
+type buildDependency struct {
+	Module   string
+	Headers  []string
+	Packages map[string]string
+	// Required dependencies fail the install if their module cannot
+	// be imported once built, others only emit a warning.
+	Required bool
+}
+
+var buildDependencies = []buildDependency{
+	{
+		Module:   "ssl",
+		Headers:  []string{"openssl/ssl.h"},
+		Packages: map[string]string{"apt-get": "libssl-dev", "dnf": "openssl-devel", "yum": "openssl-devel", "apk": "openssl-dev", "brew": "openssl"},
+		Required: true,
+	},
+	{
+		Module:   "sqlite3",
+		Headers:  []string{"sqlite3.h"},
+		Packages: map[string]string{"apt-get": "libsqlite3-dev", "dnf": "sqlite-devel", "yum": "sqlite-devel", "apk": "sqlite-dev", "brew": "sqlite"},
+	},
+	{
+		Module:   "ctypes",
+		Headers:  []string{"ffi.h"},
+		Packages: map[string]string{"apt-get": "libffi-dev", "dnf": "libffi-devel", "yum": "libffi-devel", "apk": "libffi-dev", "brew": "libffi"},
+		Required: true,
+	},
+	{
+		Module:   "lzma",
+		Headers:  []string{"lzma.h"},
+		Packages: map[string]string{"apt-get": "liblzma-dev", "dnf": "xz-devel", "yum": "xz-devel", "apk": "xz-dev", "brew": "xz"},
+	},
+	{
+		Module:   "bz2",
+		Headers:  []string{"bzlib.h"},
+		Packages: map[string]string{"apt-get": "libbz2-dev", "dnf": "bzip2-devel", "yum": "bzip2-devel", "apk": "bzip2-dev", "brew": "bzip2"},
+	},
+	{
+		Module:   "readline",
+		Headers:  []string{"readline/readline.h"},
+		Packages: map[string]string{"apt-get": "libreadline-dev", "dnf": "readline-devel", "yum": "readline-devel", "apk": "readline-dev", "brew": "readline"},
+	},
+	{
+		Module:   "zlib",
+		Headers:  []string{"zlib.h"},
+		Packages: map[string]string{"apt-get": "zlib1g-dev", "dnf": "zlib-devel", "yum": "zlib-devel", "apk": "zlib-dev", "brew": "zlib"},
+		Required: true,
+	},
+}
+
+var defaultIncludeDirs = []string{
+	"/usr/include",
+	"/usr/local/include",
+	"/opt/homebrew/include",
+}
+
+// Package managers probed (in order) to suggest install commands.
+var packageManagers = []string{"apt-get", "dnf", "yum", "apk", "brew"}
+
+// Returns the directories searched for development headers. On top of the
+// default locations, multiarch include directories and any -I flag passed
+// via CPPFLAGS or CFLAGS (from the process' environment or the build
+// environment <buildEnv>) are considered.
+func getIncludeDirs(buildEnv []string) []string {
+	dirs := append([]string{}, defaultIncludeDirs...)
+
+	multiarchDirs, _ := filepath.Glob("/usr/include/*-linux-gnu*")
+	dirs = append(dirs, multiarchDirs...)
+
+	flagValues := []string{os.Getenv("CPPFLAGS"), os.Getenv("CFLAGS")}
+
+	for _, envVar := range buildEnv {
+		if key, value, _ := strings.Cut(envVar, "="); key == "CPPFLAGS" || key == "CFLAGS" {
+			flagValues = append(flagValues, value)
+		}
+	}
+
+	for _, flagValue := range flagValues {
+		for _, flag := range strings.Fields(flagValue) {
+			if includeDir, isInclude := strings.CutPrefix(flag, "-I"); isInclude && includeDir != "" {
+				dirs = append(dirs, includeDir)
+			}
+		}
+	}
+
+	return dirs
+}
+
+// Returns the build dependencies for which no header could be found in any
+// of the given include directories.
+func findMissingBuildDependencies(includeDirs []string) []buildDependency {
+	missing := []buildDependency{}
+
+	for _, dependency := range buildDependencies {
+		found := false
+
+		for _, header := range dependency.Headers {
+			for _, dir := range includeDirs {
+				if _, err := os.Stat(path.Join(dir, header)); err == nil {
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			missing = append(missing, dependency)
+		}
+	}
+
+	return missing
+}
+
+// Formats a suggestion of the command to run to install the packages
+// providing the given dependencies, based on the first package manager
+// found on the system.
+func formatInstallHint(dependencies []buildDependency) string {
+	for _, manager := range packageManagers {
+		if _, err := exec.LookPath(manager); err != nil {
+			continue
+		}
+
+		packages := []string{}
+		for _, dependency := range dependencies {
+			packages = append(packages, dependency.Packages[manager])
+		}
+
+		return manager + " install " + strings.Join(packages, " ")
+	}
+
+	hints := []string{}
+	for _, dependency := range dependencies {
+		hints = append(hints, dependency.Packages["apt-get"])
+	}
+
+	return "install the development packages for your platform (e.g. " + strings.Join(hints, " ") + ")"
+}
+
+func listModules(dependencies []buildDependency) string {
+	modules := []string{}
+
+	for _, dependency := range dependencies {
+		modules = append(modules, dependency.Module)
+	}
+
+	return strings.Join(modules, ", ")
+}
+
+// Warns about development headers missing from the system before the
+// source is configured. Header detection can miss non-standard layouts,
+// so the post-build check (see: verifyBuiltModules) is the one that fails
+// the install.
+func checkBuildDependencies(buildEnv []string) {
+	missing := findMissingBuildDependencies(getIncludeDirs(buildEnv))
+
+	if len(missing) == 0 {
+		logger.InfoLogger.Println("All build dependencies found")
+		return
+	}
+
+	logger.InfoLogger.Println(logger.Bold(logger.Yellow("WARNING: Missing development headers for: " + listModules(missing))))
+	logger.InfoLogger.Println(logger.Bold(logger.Yellow("These modules will likely not be built. To fix: " + formatInstallHint(missing))))
+}
+
+// Imports the extension modules that depend on optional system libraries
+// using the freshly built interpreter. An error is returned if any required
+// module cannot be imported; optional ones are reported as warnings.
+func verifyBuiltModules(interpreterPath string, log io.Writer) error {
+	failedRequired := []buildDependency{}
+	failedOptional := []buildDependency{}
+
+	for _, dependency := range buildDependencies {
+		if _, err := vexec.RunCommandWithOptions([]string{interpreterPath, "-c", "import " + dependency.Module}, state.GetStatePath(), vexec.CommandOptions{Log: log}); err == nil {
+			continue
+		}
+
+		if dependency.Required {
+			failedRequired = append(failedRequired, dependency)
+		} else {
+			failedOptional = append(failedOptional, dependency)
+		}
+	}
+
+	if len(failedOptional) != 0 {
+		logger.InfoLogger.Println(logger.Bold(logger.Yellow("WARNING: The following modules were not built: " + listModules(failedOptional))))
+		logger.InfoLogger.Println(logger.Bold(logger.Yellow("To fix, reinstall after running: " + formatInstallHint(failedOptional))))
+	}
+
+	if len(failedRequired) != 0 {
+		return errors.New("Required modules were not built: " + listModules(failedRequired) + ". Reinstall after running: " + formatInstallHint(failedRequired))
+	}
+
+	return nil
+}
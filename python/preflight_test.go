@@ -0,0 +1,74 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	testutils "v/testutils"
+)
+
+func TestFindMissingBuildDependenciesReportsAllIfNoHeaders(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	missing := findMissingBuildDependencies([]string{t.TempDir()})
+
+	if len(missing) != len(buildDependencies) {
+		t.Errorf("Expected %d missing dependencies, got %d.", len(buildDependencies), len(missing))
+	}
+}
+
+func TestFindMissingBuildDependenciesSkipsFoundHeaders(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	includeDir := t.TempDir()
+	os.MkdirAll(path.Join(includeDir, "openssl"), 0750)
+	os.WriteFile(path.Join(includeDir, "openssl", "ssl.h"), []byte(""), 0750)
+	os.WriteFile(path.Join(includeDir, "zlib.h"), []byte(""), 0750)
+
+	missing := findMissingBuildDependencies([]string{includeDir})
+
+	for _, dependency := range missing {
+		if dependency.Module == "ssl" || dependency.Module == "zlib" {
+			t.Errorf("Did not expect %s to be reported missing.", dependency.Module)
+		}
+	}
+
+	if len(missing) != len(buildDependencies)-2 {
+		t.Errorf("Expected %d missing dependencies, got %d.", len(buildDependencies)-2, len(missing))
+	}
+}
+
+func TestVerifyBuiltModulesFailsIfRequiredModuleMissing(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	mockInterpreter := path.Join(t.TempDir(), "python")
+	os.WriteFile(mockInterpreter, []byte("#!/bin/bash\n[[ \"$2\" == \"import ssl\" ]] && exit 1\nexit 0"), 0777)
+
+	err := verifyBuiltModules(mockInterpreter, nil)
+
+	if err == nil || !strings.Contains(err.Error(), "ssl") {
+		t.Errorf("Expected error mentioning ssl, got %v.", err)
+	}
+}
+
+func TestVerifyBuiltModulesWarnsIfOptionalModuleMissing(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	mockInterpreter := path.Join(t.TempDir(), "python")
+	os.WriteFile(mockInterpreter, []byte("#!/bin/bash\n[[ \"$2\" == \"import sqlite3\" ]] && exit 1\nexit 0"), 0777)
+
+	err := verifyBuiltModules(mockInterpreter, nil)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "sqlite3") {
+		t.Errorf("Expected warning mentioning sqlite3, got %s", out.String())
+	}
+}
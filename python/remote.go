@@ -0,0 +1,84 @@
+package python
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	download "v/download"
+)
+
+// Matches links to release directories in a mirror's index page.
+var remoteVersionPattern = regexp.MustCompile(`href="(\d+\.\d+\.\d+)/?"`)
+
+// Extracts release versions from a mirror's index page, sorted in ascending order.
+func parseRemoteVersions(index string) []string {
+	versions := []string{}
+
+	for _, match := range remoteVersionPattern.FindAllStringSubmatch(index, -1) {
+		if !slices.Contains(versions, match[1]) {
+			versions = append(versions, match[1])
+		}
+	}
+
+	slices.SortFunc(versions, CompareVersions)
+
+	return versions
+}
+
+// Lists the release directories of a local (file://) mirror.
+func listLocalMirrorVersions(baseURL string) ([]string, error) {
+	parsed, err := url.Parse(baseURL)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	entries, err := os.ReadDir(parsed.Path)
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	index := ""
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			index += `href="` + entry.Name() + `/"` + "\n"
+		}
+	}
+
+	return parseRemoteVersions(index), nil
+}
+
+// ListRemoteVersions returns the versions available for download from the
+// first reachable mirror, sorted in ascending order. In offline mode, only
+// local mirrors are considered.
+func ListRemoteVersions(offline bool) ([]string, error) {
+	versions := []string{}
+
+	err := tryMirrors(offline, func(baseURL string) error {
+		if download.IsLocalURL(baseURL) {
+			localVersions, err := listLocalMirrorVersions(baseURL)
+			versions = localVersions
+			return err
+		}
+
+		indexURL, err := url.JoinPath(baseURL, "/")
+
+		if err != nil {
+			return err
+		}
+
+		index, err := download.Fetch(indexURL)
+
+		if err != nil {
+			return err
+		}
+
+		versions = parseRemoteVersions(string(index))
+		return nil
+	})
+
+	return versions, err
+}
@@ -0,0 +1,69 @@
+package python
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"slices"
+	"testing"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+var mockIndex = `<html><body>
+<a href="../">../</a>
+<a href="3.10.0/">3.10.0/</a>
+<a href="3.9.18/">3.9.18/</a>
+<a href="3.10.12/">3.10.12/</a>
+<a href="index-windows.json">index-windows.json</a>
+</body></html>`
+
+func TestParseRemoteVersionsSortsVersions(t *testing.T) {
+	versions := parseRemoteVersions(mockIndex)
+
+	if !slices.Equal(versions, []string{"3.9.18", "3.10.0", "3.10.12"}) {
+		t.Errorf("Unexpected versions: %v", versions)
+	}
+}
+
+func TestListRemoteVersionsFallsBackToNextMirror(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"download": {"retries": 0}}`), 0750)
+
+	brokenMirror := httptest.NewServer(http.NotFoundHandler())
+	defer brokenMirror.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(mockIndex))
+	}))
+	defer mirror.Close()
+
+	t.Setenv(mirrorsEnvVar, brokenMirror.URL+","+mirror.URL)
+
+	versions, err := ListRemoteVersions(false)
+
+	if err != nil || !slices.Equal(versions, []string{"3.9.18", "3.10.0", "3.10.12"}) {
+		t.Errorf("Unexpected versions: %v (%v)", versions, err)
+	}
+}
+
+func TestListRemoteVersionsReadsLocalMirror(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	mirrorPath := t.TempDir()
+	for _, version := range []string{"3.11.4", "3.9.18"} {
+		os.MkdirAll(path.Join(mirrorPath, version), 0750)
+	}
+
+	t.Setenv(mirrorsEnvVar, "https://www.python.org/ftp/python,file://"+mirrorPath)
+
+	versions, err := ListRemoteVersions(true)
+
+	if err != nil || !slices.Equal(versions, []string{"3.9.18", "3.11.4"}) {
+		t.Errorf("Unexpected versions: %v (%v)", versions, err)
+	}
+}
@@ -0,0 +1,244 @@
+package python
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	cli "v/cli"
+	download "v/download"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Directories searched for interpreters after those on PATH.
+var commonInterpreterPrefixes = []string{"/usr/local/bin", "/usr/bin", "/bin", "/opt/homebrew/bin", "/opt/local/bin"}
+
+// Matches the names of interpreters (python, python3, python3.12).
+var systemInterpreterPattern = regexp.MustCompile(`^python(\d+(\.\d+)?)?$`)
+
+// Matches the version printed by `python --version`.
+var interpreterVersionPattern = regexp.MustCompile(`Python (\d+\.\d+\.\d+)`)
+
+// Interpreter found outside of v's runtimes.
+type SystemInterpreter struct {
+	Path    string
+	Version string
+}
+
+// Returns the version of the interpreter at <interpreterPath>.
+func getInterpreterVersion(interpreterPath string) (string, error) {
+	versionOut, err := exec.RunCommand([]string{interpreterPath, "--version"}, state.GetStatePath())
+
+	if err != nil {
+		return "", err
+	}
+
+	match := interpreterVersionPattern.FindStringSubmatch(versionOut)
+
+	if match == nil {
+		return "", errors.New("Cannot determine the version of " + interpreterPath)
+	}
+
+	return match[1], nil
+}
+
+// Returns the directories to search for interpreters: PATH entries, then
+// common prefixes. v's own directories (i.e. shims) are excluded.
+func getInterpreterSearchPath() []string {
+	directories := []string{}
+	stateRoot := filepath.Clean(state.GetStatePath())
+
+	for _, directory := range append(filepath.SplitList(os.Getenv("PATH")), commonInterpreterPrefixes...) {
+		if directory == "" {
+			continue
+		}
+
+		directory = filepath.Clean(directory)
+
+		if directory == stateRoot || strings.HasPrefix(directory, stateRoot+string(filepath.Separator)) || slices.Contains(directories, directory) {
+			continue
+		}
+
+		directories = append(directories, directory)
+	}
+
+	return directories
+}
+
+// DiscoverSystemInterpreters returns the interpreters found on PATH and in
+// common prefixes, in search order. Names pointing to the same interpreter
+// (i.e. python3 -> python3.12) are only reported once, under the first one found.
+func DiscoverSystemInterpreters() []SystemInterpreter {
+	return findSystemInterpreters(false)
+}
+
+// Searches for interpreters (see: DiscoverSystemInterpreters), stopping at the
+// first one found if <firstOnly> is set. Each candidate is run to get its
+// version, which makes a full search slow.
+func findSystemInterpreters(firstOnly bool) []SystemInterpreter {
+	interpreters := []SystemInterpreter{}
+	seen := []string{}
+
+	for _, directory := range getInterpreterSearchPath() {
+		entries, _ := os.ReadDir(directory)
+		names := []string{}
+
+		for _, entry := range entries {
+			if systemInterpreterPattern.MatchString(entry.Name()) {
+				names = append(names, entry.Name())
+			}
+		}
+
+		// Generic names first, so that python3 is preferred over python3.12.
+		slices.SortFunc(names, func(a, b string) int {
+			return len(a) - len(b)
+		})
+
+		for _, name := range names {
+			interpreterPath := path.Join(directory, name)
+			realPath, err := filepath.EvalSymlinks(interpreterPath)
+
+			if err != nil || slices.Contains(seen, realPath) {
+				continue
+			}
+
+			seen = append(seen, realPath)
+
+			version, err := getInterpreterVersion(interpreterPath)
+
+			if err != nil {
+				continue
+			}
+
+			interpreters = append(interpreters, SystemInterpreter{Path: interpreterPath, Version: version})
+
+			if firstOnly {
+				return interpreters
+			}
+		}
+	}
+
+	return interpreters
+}
+
+// Lists the interpreters found outside of v, which can be registered
+// with `v python link`.
+func listSystemInterpreters(args []string, flags cli.Flags, currentState state.State) error {
+	interpreters := DiscoverSystemInterpreters()
+
+	if len(interpreters) == 0 {
+		logger.InfoLogger.Println("No system interpreters found!")
+		return nil
+	}
+
+	for _, interpreter := range interpreters {
+		logger.InfoLogger.Printf("%-10s%s\n", interpreter.Version, interpreter.Path)
+	}
+
+	return nil
+}
+
+// Returns the prefix and the interpreter (relative to the prefix) designated
+// by <target>: either the path to an interpreter, or a prefix holding a
+// single versioned interpreter in its bin directory.
+func resolveLinkTarget(target string) (string, string, error) {
+	absoluteTarget, err := filepath.Abs(target)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	info, err := os.Stat(absoluteTarget)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if !info.IsDir() {
+		prefix := path.Dir(path.Dir(absoluteTarget))
+		executable, _ := filepath.Rel(prefix, absoluteTarget)
+
+		return prefix, executable, nil
+	}
+
+	interpreters := findInterpreters(absoluteTarget)
+
+	if len(interpreters) == 0 {
+		return "", "", errors.New("No interpreter found in " + path.Join(absoluteTarget, "bin"))
+	}
+
+	if len(interpreters) > 1 {
+		names := []string{}
+
+		for _, interpreter := range interpreters {
+			names = append(names, path.Base(interpreter))
+		}
+
+		return "", "", errors.New("Several interpreters found in " + path.Join(absoluteTarget, "bin") + ": " + strings.Join(names, ", ") + ". Pass the path to one of them instead.")
+	}
+
+	executable, _ := filepath.Rel(absoluteTarget, interpreters[0])
+
+	return absoluteTarget, executable, nil
+}
+
+// Link (called via `v python link <name> <path>`) registers an interpreter
+// installed outside of v (distribution packages, deadsnakes, conda, ...) as
+// a version that can be selected like any other. Nothing is copied: the
+// install only holds a manifest pointing to the external prefix.
+func link(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 3 {
+		return errors.New("Usage: v python link <name> <path-to-prefix-or-interpreter>")
+	}
+
+	name := positional[1]
+
+	if err := ValidateInstallName(name); err != nil {
+		return err
+	}
+
+	if slices.Contains(GetAvailableVersions(), name) {
+		return errors.New("Python " + name + " is already installed.")
+	}
+
+	prefix, executable, err := resolveLinkTarget(positional[2])
+
+	if err != nil {
+		return err
+	}
+
+	version, err := getInterpreterVersion(path.Join(prefix, executable))
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(state.GetStatePath("runtimes", "python", name), 0775); err != nil {
+		return err
+	}
+
+	err = WriteManifest(name, Manifest{
+		Version:     name,
+		SourceURL:   download.FileURL(prefix),
+		Prefix:      prefix,
+		Executable:  executable,
+		VVersion:    ToolVersion,
+		InstalledAt: time.Now().UTC(),
+		Host:        getHostInfo(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Linked %s to Python %s at %s\n", logger.Bold(name), version, path.Join(prefix, executable))
+	return nil
+}
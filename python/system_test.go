@@ -0,0 +1,107 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Creates mock interpreters printing the given versions in a directory set
+// as the only place searched for system interpreters, which is returned.
+func setupSystemInterpreters(t *testing.T, interpreters map[string]string) string {
+	directory := t.TempDir()
+
+	for name, version := range interpreters {
+		os.WriteFile(path.Join(directory, name), []byte("#!/bin/sh\necho \"Python "+version+"\""), 0777)
+	}
+
+	previousPrefixes := commonInterpreterPrefixes
+	commonInterpreterPrefixes = []string{}
+	t.Cleanup(func() { commonInterpreterPrefixes = previousPrefixes })
+	t.Setenv("PATH", directory+":/usr/bin")
+
+	return directory
+}
+
+func TestDiscoverSystemInterpretersSkipsShimsAndDuplicates(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(state.GetStatePath("shims"), 0750)
+	os.WriteFile(state.GetStatePath("shims", "python"), []byte("#!/bin/sh\necho \"Python 4.5.6\""), 0777)
+
+	directory := setupSystemInterpreters(t, map[string]string{"python3.12": "3.12.1", "python3.11": "3.11.7"})
+	os.Symlink(path.Join(directory, "python3.12"), path.Join(directory, "python3"))
+	t.Setenv("PATH", state.GetStatePath("shims")+":"+directory)
+
+	interpreters := DiscoverSystemInterpreters()
+
+	if len(interpreters) != 2 {
+		t.Fatalf("Expected 2 interpreters, got %v", interpreters)
+	}
+
+	if interpreters[0].Path != path.Join(directory, "python3") || interpreters[0].Version != "3.12.1" {
+		t.Errorf("Expected python3 to be reported first, got %v", interpreters[0])
+	}
+
+	if interpreters[1].Path != path.Join(directory, "python3.11") {
+		t.Errorf("Unexpected interpreter: %v", interpreters[1])
+	}
+}
+
+func TestResolveLinkTargetAcceptsInterpreterPath(t *testing.T) {
+	prefix := t.TempDir()
+	os.MkdirAll(path.Join(prefix, "bin"), 0750)
+	os.WriteFile(path.Join(prefix, "bin", "python3.8"), []byte(""), 0750)
+	os.WriteFile(path.Join(prefix, "bin", "python3.12"), []byte(""), 0750)
+
+	if _, _, err := resolveLinkTarget(prefix); err == nil || !strings.Contains(err.Error(), "Several interpreters") {
+		t.Errorf("Expected an ambiguity error, got %v", err)
+	}
+
+	resolvedPrefix, executable, err := resolveLinkTarget(path.Join(prefix, "bin", "python3.8"))
+
+	if err != nil || resolvedPrefix != prefix || executable != "bin/python3.8" {
+		t.Errorf("Unexpected result: %s, %s (%v)", resolvedPrefix, executable, err)
+	}
+}
+
+func TestLinkRegistersExternalInterpreter(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	setupDownloadTest(t)
+
+	prefix := t.TempDir()
+	os.MkdirAll(path.Join(prefix, "bin"), 0750)
+	os.WriteFile(path.Join(prefix, "bin", "python3.9"), []byte("#!/bin/sh\necho \"Python 3.9.18\""), 0777)
+
+	if err := link([]string{"link", "distro-3.9", prefix}, cli.Flags{}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if interpreterPath := GetInterpreterPath("distro-3.9"); interpreterPath != path.Join(prefix, "bin", "python3.9") {
+		t.Errorf("Unexpected interpreter path: %s", interpreterPath)
+	}
+
+	if _, err := os.Stat(path.Join(prefix, manifestFilename)); err == nil {
+		t.Errorf("Did not expect the manifest to be written in the external prefix.")
+	}
+}
+
+func TestDetermineSystemPythonOnlyRunsFirstInterpreter(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	directory := setupSystemInterpreters(t, map[string]string{"python3": "3.12.1"})
+	markerPath := path.Join(t.TempDir(), "ran")
+	os.WriteFile(path.Join(directory, "python3.11"), []byte("#!/bin/sh\ntouch "+markerPath+"\necho \"Python 3.11.7\""), 0777)
+
+	if version, _ := DetermineSystemPython(); version != "3.12.1" {
+		t.Errorf("Expected 3.12.1, got %s", version)
+	}
+
+	if _, err := os.Stat(markerPath); !os.IsNotExist(err) {
+		t.Errorf("Did not expect other interpreters to be run.")
+	}
+}
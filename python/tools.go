@@ -0,0 +1,505 @@
+package python
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	cli "v/cli"
+	download "v/download"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+const toolMetadataFilename = ".v-tool.json"
+
+// Matches package requirements (i.e. black, black[d]==24.1.0), capturing
+// the distribution name.
+var toolRequirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*([<>=!~].*)?$`)
+
+// Prints the version and console scripts of a distribution as JSON.
+const toolDetailsScript = `import importlib.metadata, json, sys
+distribution = importlib.metadata.distribution(sys.argv[1])
+print(json.dumps({"version": distribution.version, "scripts": sorted(e.name for e in distribution.entry_points if e.group == "console_scripts")}))`
+
+// Metadata written in the virtual environment of each tool.
+type ToolMetadata struct {
+	Name string `json:"name"`
+	// Requirement passed to pip (i.e. black==24.1.0).
+	Requirement string `json:"requirement"`
+	Version     string `json:"version"`
+	// Version the tool's environment was created from, or "system".
+	Runtime     string    `json:"runtime"`
+	EntryPoints []string  `json:"entryPoints"`
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+type toolDetails struct {
+	Version string   `json:"version"`
+	Scripts []string `json:"scripts"`
+}
+
+func GetToolPath(name string) string {
+	return state.GetStatePath("tools", name)
+}
+
+func ReadToolMetadata(name string) (ToolMetadata, error) {
+	metadata := ToolMetadata{}
+
+	c, err := os.ReadFile(path.Join(GetToolPath(name), toolMetadataFilename))
+
+	if err != nil {
+		return metadata, err
+	}
+
+	err = json.Unmarshal(c, &metadata)
+
+	return metadata, err
+}
+
+func writeToolMetadata(metadata ToolMetadata) error {
+	d, err := json.MarshalIndent(metadata, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join(GetToolPath(metadata.Name), toolMetadataFilename), d, 0640)
+}
+
+// ListTools returns the metadata of all installed tools, in name order.
+func ListTools() ([]ToolMetadata, error) {
+	entries, err := os.ReadDir(state.GetStatePath("tools"))
+
+	if os.IsNotExist(err) {
+		return []ToolMetadata{}, nil
+	}
+
+	if err != nil {
+		return []ToolMetadata{}, err
+	}
+
+	tools := []ToolMetadata{}
+
+	for _, entry := range entries {
+		// Environments set aside during changes hold the metadata of another name.
+		if metadata, err := ReadToolMetadata(entry.Name()); err == nil && metadata.Name == entry.Name() {
+			tools = append(tools, metadata)
+		}
+	}
+
+	return tools, nil
+}
+
+// Returns the names of the tools installed with <version>.
+func getToolsUsing(version string) []string {
+	tools, _ := ListTools()
+	names := []string{}
+
+	for _, tool := range tools {
+		if tool.Runtime == version {
+			names = append(names, tool.Name)
+		}
+	}
+
+	return names
+}
+
+// Returns whether the runtime of <tool> is still available.
+func isToolRuntimeInstalled(tool ToolMetadata) bool {
+	return tool.Runtime == "system" || slices.Contains(GetAvailableVersions(), tool.Runtime)
+}
+
+// Matches the separators that are equivalent in distribution names.
+var toolNameSeparatorPattern = regexp.MustCompile(`[-_.]+`)
+
+// Returns the distribution name of the requirement <requirement>, normalized
+// as per PEP 503 (i.e. Foo_Bar and foo-bar are the same tool).
+func getToolName(requirement string) (string, error) {
+	match := toolRequirementPattern.FindStringSubmatch(strings.TrimSpace(requirement))
+
+	if match == nil {
+		return "", errors.New("Invalid package: " + requirement + ". Expected a package name, optionally with extras and a version specifier.")
+	}
+
+	return toolNameSeparatorPattern.ReplaceAllString(strings.ToLower(match[1]), "-"), nil
+}
+
+// Returns the entry points of the other tools and the shims managed by v,
+// which a tool cannot shadow.
+func getReservedShims(exceptTool string) []string {
+	reserved := []string{}
+
+	for shimName := range Shims {
+		reserved = append(reserved, shimName)
+	}
+
+	tools, _ := ListTools()
+
+	for _, tool := range tools {
+		if tool.Name != exceptTool {
+			reserved = append(reserved, tool.EntryPoints...)
+		}
+	}
+
+	return reserved
+}
+
+// Replaces the shims of <previous> entry points by shims of the entry points of <tool>.
+func syncToolShims(tool ToolMetadata, previous []string) error {
+	for _, entryPoint := range previous {
+		if !slices.Contains(tool.EntryPoints, entryPoint) {
+			os.Remove(state.GetStatePath("shims", entryPoint))
+		}
+	}
+
+	if err := os.MkdirAll(state.GetStatePath("shims"), 0775); err != nil {
+		return err
+	}
+
+	for _, entryPoint := range tool.EntryPoints {
+		shimContent := []byte("#!/bin/bash\n" + path.Join(GetToolPath(tool.Name), "bin", entryPoint) + " \"$@\"")
+
+		if err := os.WriteFile(state.GetStatePath("shims", entryPoint), shimContent, 0775); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns where the environment of the tool <name> is set aside while it
+// changes. Environments cannot be relocated (scripts point to their
+// interpreter), so changes are made in place and undone by restoring it.
+func getToolBackupPath(name string) string {
+	return state.GetStatePath("tools", "."+name+".previous")
+}
+
+// Replaces the environment of the tool <name> by the one set aside before a
+// change that failed with <failure>, if any, and returns <failure>.
+func restoreToolEnvironment(name string, failure error) error {
+	toolPath := GetToolPath(name)
+	backupPath := getToolBackupPath(name)
+
+	os.RemoveAll(toolPath)
+
+	if !fileExists(backupPath) {
+		return failure
+	}
+
+	if err := os.Rename(backupPath, toolPath); err != nil {
+		return errors.New(failure.Error() + " Restoring the previous environment failed: " + err.Error())
+	}
+
+	return failure
+}
+
+// Creates the environment of <tool> from <runtime>, installs the tool into it
+// and exposes its entry points as shims. Any previous environment is replaced,
+// and restored if the install fails.
+func installTool(tool ToolMetadata, previous []string, currentState state.State) (ToolMetadata, error) {
+	runtimeVersion, interpreterPath, err := resolveVenvRuntime(tool.Runtime, currentState)
+
+	if err != nil {
+		return tool, err
+	}
+
+	tool.Runtime = runtimeVersion
+	toolPath := GetToolPath(tool.Name)
+	backupPath := getToolBackupPath(tool.Name)
+
+	if err := os.RemoveAll(backupPath); err != nil {
+		return tool, err
+	}
+
+	if err := os.MkdirAll(state.GetStatePath("tools"), 0775); err != nil {
+		return tool, err
+	}
+
+	if fileExists(toolPath) {
+		if err := os.Rename(toolPath, backupPath); err != nil {
+			return tool, err
+		}
+	}
+
+	logger.InfoLogger.Printf("Installing %s with Python %s\n", logger.Bold(tool.Requirement), tool.Runtime)
+
+	if err := createToolEnvironment(&tool, interpreterPath, previous); err != nil {
+		return tool, restoreToolEnvironment(tool.Name, err)
+	}
+
+	return tool, os.RemoveAll(backupPath)
+}
+
+// Upgrades the package of <tool> in its environment. The environment is
+// copied aside first, and restored if the upgrade cannot be completed (i.e.
+// the new version has entry points conflicting with existing shims).
+func upgradeTool(tool ToolMetadata) (ToolMetadata, error) {
+	backupPath := getToolBackupPath(tool.Name)
+
+	if err := os.RemoveAll(backupPath); err != nil {
+		return tool, err
+	}
+
+	if output, err := exec.RunCommand([]string{"cp", "-a", GetToolPath(tool.Name), backupPath}, state.GetStatePath()); err != nil {
+		return tool, errors.New("Failed to back up the environment of " + tool.Name + ": " + describeCommandFailure(output, err))
+	}
+
+	if err := upgradeToolPackage(&tool, true, tool.EntryPoints); err != nil {
+		return tool, restoreToolEnvironment(tool.Name, err)
+	}
+
+	return tool, os.RemoveAll(backupPath)
+}
+
+// Creates the environment of <tool> with the interpreter at <interpreterPath>
+// and installs the tool into it (see: upgradeToolPackage).
+func createToolEnvironment(tool *ToolMetadata, interpreterPath string, previous []string) error {
+	if output, err := exec.RunCommand([]string{interpreterPath, "-m", "venv", GetToolPath(tool.Name)}, state.GetStatePath()); err != nil {
+		return errors.New("Failed to create the environment of " + tool.Name + ": " + describeCommandFailure(output, err))
+	}
+
+	return upgradeToolPackage(tool, false, previous)
+}
+
+// Installs (or upgrades) the package of <tool> in its environment, then
+// records its version and entry points and updates its shims.
+func upgradeToolPackage(tool *ToolMetadata, upgrade bool, previous []string) error {
+	toolInterpreter := path.Join(GetToolPath(tool.Name), "bin", "python")
+	command := []string{toolInterpreter, "-m", "pip", "install", "--quiet"}
+
+	if upgrade {
+		command = append(command, "--upgrade")
+	}
+
+	env, err := download.SubprocessEnv()
+
+	if err != nil {
+		return err
+	}
+
+	if output, err := exec.RunCommandWithOptions(append(command, tool.Requirement), state.GetStatePath(), exec.CommandOptions{Env: env}); err != nil {
+		return errors.New("Failed to install " + tool.Requirement + ": " + describeCommandFailure(output, err))
+	}
+
+	detailsOut, err := exec.RunCommand([]string{toolInterpreter, "-c", toolDetailsScript, tool.Name}, state.GetStatePath())
+
+	if err != nil {
+		return errors.New("Failed to read the entry points of " + tool.Name + ": " + describeCommandFailure(detailsOut, err))
+	}
+
+	details := toolDetails{}
+
+	if err := json.Unmarshal([]byte(detailsOut), &details); err != nil {
+		return err
+	}
+
+	reserved := getReservedShims(tool.Name)
+
+	for _, script := range details.Scripts {
+		if slices.Contains(reserved, script) {
+			return errors.New("Entry point " + script + " of " + tool.Name + " conflicts with an existing shim.")
+		}
+	}
+
+	tool.Version = details.Version
+	tool.EntryPoints = details.Scripts
+	tool.InstalledAt = time.Now().UTC()
+
+	if err := writeToolMetadata(*tool); err != nil {
+		return err
+	}
+
+	return syncToolShims(*tool, previous)
+}
+
+func installToolCommand(args []string, flags cli.Flags, currentState state.State) error {
+	if len(args) < 2 {
+		return errors.New("Missing package to install.")
+	}
+
+	name, err := getToolName(args[1])
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := ReadToolMetadata(name); err == nil {
+		return errors.New(name + " is already installed. Use `v python tool upgrade " + name + "` or `v python tool reinstall " + name + "`.")
+	}
+
+	tool, err := installTool(ToolMetadata{Name: name, Requirement: args[1], Runtime: flags.Python}, []string{}, currentState)
+
+	if err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Installed %s %s: %s\n", tool.Name, tool.Version, strings.Join(tool.EntryPoints, ", "))
+	return nil
+}
+
+func listToolsCommand(args []string, flags cli.Flags, currentState state.State) error {
+	tools, err := ListTools()
+
+	if err != nil {
+		return err
+	}
+
+	if len(tools) == 0 {
+		logger.InfoLogger.Println("No tools installed!")
+		return nil
+	}
+
+	for _, tool := range tools {
+		line := tool.Name + " " + tool.Version + " (Python " + tool.Runtime + "): " + strings.Join(tool.EntryPoints, ", ")
+
+		if !isToolRuntimeInstalled(tool) {
+			line += logger.Yellow(" (runtime not installed, see: v python tool reinstall " + tool.Name + ")")
+		}
+
+		logger.InfoLogger.Println(line)
+	}
+
+	return nil
+}
+
+// Returns the tools named in <args>, or all tools if none are named.
+func selectTools(args []string) ([]ToolMetadata, error) {
+	if len(args) < 2 {
+		return ListTools()
+	}
+
+	tools := []ToolMetadata{}
+
+	for _, name := range args[1:] {
+		if err := ValidateInstallName(name); err != nil {
+			return []ToolMetadata{}, err
+		}
+
+		tool, err := ReadToolMetadata(name)
+
+		if err != nil {
+			return []ToolMetadata{}, errors.New(name + " is not installed.")
+		}
+
+		tools = append(tools, tool)
+	}
+
+	return tools, nil
+}
+
+func upgradeToolsCommand(args []string, flags cli.Flags, currentState state.State) error {
+	tools, err := selectTools(args)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tool := range tools {
+		if !isToolRuntimeInstalled(tool) {
+			logger.InfoLogger.Println(logger.Yellow("Skipping " + tool.Name + ": Python " + tool.Runtime + " is not installed. Reinstall it instead."))
+			continue
+		}
+
+		upgraded, err := upgradeTool(tool)
+
+		if err != nil {
+			return err
+		}
+
+		logger.InfoLogger.Printf("%s: %s -> %s\n", tool.Name, tool.Version, upgraded.Version)
+	}
+
+	return nil
+}
+
+// Recreates the environment of the named tools, or of all tools whose runtime
+// was uninstalled if none are named. Tools are reinstalled with the version
+// passed via --python, their original runtime if still installed, or the
+// selected version otherwise.
+func reinstallToolsCommand(args []string, flags cli.Flags, currentState state.State) error {
+	tools, err := selectTools(args)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tool := range tools {
+		if len(args) < 2 && isToolRuntimeInstalled(tool) {
+			continue
+		}
+
+		if flags.Python != "" || !isToolRuntimeInstalled(tool) {
+			tool.Runtime = flags.Python
+		}
+
+		reinstalled, err := installTool(tool, tool.EntryPoints, currentState)
+
+		if err != nil {
+			return err
+		}
+
+		logger.InfoLogger.Printf("Reinstalled %s %s with Python %s\n", reinstalled.Name, reinstalled.Version, reinstalled.Runtime)
+	}
+
+	return nil
+}
+
+func uninstallToolCommand(args []string, flags cli.Flags, currentState state.State) error {
+	if len(args) < 2 {
+		return errors.New("Missing tool to uninstall.")
+	}
+
+	if ValidateInstallName(args[1]) != nil {
+		return errors.New(args[1] + " is not installed.")
+	}
+
+	tool, err := ReadToolMetadata(args[1])
+
+	if err != nil {
+		return errors.New(args[1] + " is not installed.")
+	}
+
+	for _, entryPoint := range tool.EntryPoints {
+		os.Remove(state.GetStatePath("shims", entryPoint))
+	}
+
+	if err := os.RemoveAll(GetToolPath(tool.Name)); err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Uninstalled %s\n", tool.Name)
+	return nil
+}
+
+var toolCommands = map[string]func([]string, cli.Flags, state.State) error{
+	"install":   installToolCommand,
+	"ls":        listToolsCommand,
+	"upgrade":   upgradeToolsCommand,
+	"reinstall": reinstallToolsCommand,
+	"uninstall": uninstallToolCommand,
+}
+
+// Tool (called via `v python tool <install|ls|upgrade|reinstall|uninstall>`)
+// manages command-line tools installed in their own virtual environment under
+// the `tools` state directory, with their entry points exposed as shims.
+func tool(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 2 {
+		return errors.New("Missing tool command: install, ls, upgrade, reinstall or uninstall.")
+	}
+
+	command, found := toolCommands[positional[1]]
+
+	if !found {
+		return errors.New("Unknown tool command: " + positional[1] + ". Expected install, ls, upgrade, reinstall or uninstall.")
+	}
+
+	return command(positional[1:], flags, currentState)
+}
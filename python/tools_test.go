@@ -0,0 +1,204 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Interpreter script creating virtual environments that report the version
+// and entry points found in their details.json file (see: setupScriptedRuntime).
+const toolScript = `mkdir -p "$3/bin"
+printf '#!/bin/sh\nif [ "$1" = "-c" ]; then cat "$(dirname "$0")/../details.json"; fi\n' > "$3/bin/python"
+chmod +x "$3/bin/python"
+echo '{"version": "1.0.0", "scripts": ["mocktool"]}' > "$3/details.json"
+`
+
+func TestGetToolName(t *testing.T) {
+	for requirement, expected := range map[string]string{"black": "black", "Black==24.1.0": "black", "httpie[socks] >= 3": "httpie", "Foo_Bar": "foo-bar", "foo.__bar": "foo-bar"} {
+		if name, err := getToolName(requirement); err != nil || name != expected {
+			t.Errorf("Expected %s for %s, got %s (%v)", expected, requirement, name, err)
+		}
+	}
+
+	if _, err := getToolName("../black"); err == nil {
+		t.Errorf("Expected an error for an invalid requirement.")
+	}
+}
+
+func TestInstallToolCreatesShims(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+	setupScriptedRuntime(t, "1.2.3", toolScript)
+
+	if err := tool([]string{"tool", "install", "mocktool==1.0.0"}, cli.Flags{Python: "1.2.3"}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	metadata, err := ReadToolMetadata("mocktool")
+
+	if err != nil || metadata.Runtime != "1.2.3" || metadata.Version != "1.0.0" || metadata.Requirement != "mocktool==1.0.0" {
+		t.Errorf("Unexpected metadata: %v (%v)", metadata, err)
+	}
+
+	shimContent, err := os.ReadFile(state.GetStatePath("shims", "mocktool"))
+
+	if err != nil || !strings.Contains(string(shimContent), path.Join(GetToolPath("mocktool"), "bin", "mocktool")) {
+		t.Errorf("Expected a shim pointing to the tool, got %s (%v)", shimContent, err)
+	}
+
+	if err := tool([]string{"tool", "install", "mocktool"}, cli.Flags{Python: "1.2.3"}, state.State{}); err == nil {
+		t.Errorf("Expected an error for an installed tool.")
+	}
+}
+
+func TestInstallToolRejectsConflictingEntryPoints(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+	setupScriptedRuntime(t, "1.2.3", toolScript)
+
+	tool([]string{"tool", "install", "mocktool"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	// Installs another tool providing the same entry point.
+	err := tool([]string{"tool", "install", "othertool"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	if err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Errorf("Expected a conflict error, got %v", err)
+	}
+
+	if _, err := os.Stat(GetToolPath("othertool")); !os.IsNotExist(err) {
+		t.Errorf("Expected the environment of the failed install to be removed.")
+	}
+}
+
+func TestUpgradeToolUpdatesShims(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+	setupScriptedRuntime(t, "1.2.3", toolScript)
+
+	tool([]string{"tool", "install", "mocktool"}, cli.Flags{Python: "1.2.3"}, state.State{})
+	os.WriteFile(path.Join(GetToolPath("mocktool"), "details.json"), []byte(`{"version": "2.0.0", "scripts": ["mocktool-ng"]}`), 0640)
+
+	if err := tool([]string{"tool", "upgrade", "mocktool"}, cli.Flags{}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if metadata, _ := ReadToolMetadata("mocktool"); metadata.Version != "2.0.0" {
+		t.Errorf("Expected version 2.0.0, got %s", metadata.Version)
+	}
+
+	if _, err := os.Stat(state.GetStatePath("shims", "mocktool")); !os.IsNotExist(err) {
+		t.Errorf("Expected the shim of the removed entry point to be removed.")
+	}
+
+	if _, err := os.Stat(state.GetStatePath("shims", "mocktool-ng")); err != nil {
+		t.Errorf("Expected a shim for the new entry point.")
+	}
+}
+
+func TestUpgradeToolRestoresEnvironmentOnConflict(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+	setupScriptedRuntime(t, "1.2.3", toolScript)
+
+	tool([]string{"tool", "install", "mocktool"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	// Upgrading installs a version with an entry point shadowing the python shim.
+	detailsPath := path.Join(GetToolPath("mocktool"), "details.json")
+	os.WriteFile(path.Join(GetToolPath("mocktool"), "bin", "python"), []byte(`#!/bin/sh
+if [ "$1" = "-c" ]; then cat "`+detailsPath+`"; fi
+case " $* " in *" --upgrade "*) echo '{"version": "2.0.0", "scripts": ["python"]}' > "`+detailsPath+`";; esac
+`), 0777)
+
+	if err := tool([]string{"tool", "upgrade", "mocktool"}, cli.Flags{}, state.State{}); err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Errorf("Expected a conflict error, got %v", err)
+	}
+
+	if details, _ := os.ReadFile(detailsPath); !strings.Contains(string(details), "1.0.0") {
+		t.Errorf("Expected the previous package to be restored, got %s", details)
+	}
+
+	if metadata, _ := ReadToolMetadata("mocktool"); metadata.Version != "1.0.0" {
+		t.Errorf("Expected version 1.0.0, got %s", metadata.Version)
+	}
+
+	if _, err := os.Stat(getToolBackupPath("mocktool")); !os.IsNotExist(err) {
+		t.Errorf("Expected the backup to be removed.")
+	}
+}
+
+func TestReinstallToolsMovesToolsOffRemovedRuntimes(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+	setupScriptedRuntime(t, "1.2.3", toolScript)
+	setupScriptedRuntime(t, "1.3.0", toolScript)
+
+	tool([]string{"tool", "install", "mocktool"}, cli.Flags{Python: "1.2.3"}, state.State{})
+	os.RemoveAll(state.GetStatePath("runtimes", "python", "1.2.3"))
+
+	if err := tool([]string{"tool", "reinstall"}, cli.Flags{Python: "1.3.0"}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if metadata, _ := ReadToolMetadata("mocktool"); metadata.Runtime != "1.3.0" {
+		t.Errorf("Expected the tool to use 1.3.0, got %s", metadata.Runtime)
+	}
+}
+
+func TestUninstallToolRemovesShims(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+	setupScriptedRuntime(t, "1.2.3", toolScript)
+
+	tool([]string{"tool", "install", "mocktool"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	if err := tool([]string{"tool", "uninstall", "mocktool"}, cli.Flags{}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, removedPath := range []string{GetToolPath("mocktool"), state.GetStatePath("shims", "mocktool")} {
+		if _, err := os.Stat(removedPath); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed.", removedPath)
+		}
+	}
+}
+
+func TestFailedReinstallRestoresPreviousEnvironment(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+	testutils.CaptureOutput(t)
+	setupScriptedRuntime(t, "1.2.3", toolScript)
+	setupScriptedRuntime(t, "1.2.4", toolScript)
+	os.WriteFile(GetInterpreterPath("1.2.4"), []byte("#!/bin/sh\nexit 1\n"), 0777)
+
+	if err := tool([]string{"tool", "install", "mocktool"}, cli.Flags{Python: "1.2.3"}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := tool([]string{"tool", "reinstall", "mocktool"}, cli.Flags{Python: "1.2.4"}, state.State{}); err == nil {
+		t.Errorf("Expected the reinstall to fail.")
+	}
+
+	if metadata, err := ReadToolMetadata("mocktool"); err != nil || metadata.Runtime != "1.2.3" {
+		t.Errorf("Expected the previous environment to be restored, got %v (%v)", metadata, err)
+	}
+
+	if _, err := os.Stat(path.Join(GetToolPath("mocktool"), "bin", "python")); err != nil {
+		t.Errorf("Expected the shims' target to be restored.")
+	}
+
+	if tools, _ := ListTools(); len(tools) != 1 {
+		t.Errorf("Expected a single tool, got %v", tools)
+	}
+}
+
+func TestUpgradeToolsRejectsInvalidNames(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	if err := tool([]string{"tool", "upgrade", "../venvs/project"}, cli.Flags{}, state.State{}); err == nil || !strings.Contains(err.Error(), "Invalid name") {
+		t.Errorf("Expected the name to be rejected, got %v", err)
+	}
+}
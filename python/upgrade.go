@@ -0,0 +1,236 @@
+package python
+
+import (
+	"errors"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	cli "v/cli"
+	download "v/download"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+// Returns the latest release of the same minor version as <version>
+// available for download.
+func findLatestPatch(version string, offline bool) (string, error) {
+	remoteVersions, err := ListRemoteVersions(offline)
+
+	if err != nil {
+		return "", err
+	}
+
+	minorVersion := VersionStringToStruct(version).MajorMinor()
+	latest := version
+
+	for _, remoteVersion := range remoteVersions {
+		if strings.HasPrefix(remoteVersion, minorVersion+".") && CompareVersions(remoteVersion, latest) > 0 {
+			latest = remoteVersion
+		}
+	}
+
+	return latest, nil
+}
+
+// Installs the packages installed in the environment of <sourceInterpreter>
+// (as reported by `pip freeze`) in the environment of <targetInterpreter>.
+// Editable installs are skipped. The requirements migrated are returned.
+func migratePackages(sourceInterpreter string, targetInterpreter string) ([]string, error) {
+	freezeOut, err := exec.RunCommand([]string{sourceInterpreter, "-m", "pip", "freeze", "--exclude-editable"}, state.GetStatePath())
+
+	if err != nil {
+		return []string{}, errors.New("Failed to list the installed packages: " + strings.TrimSpace(freezeOut))
+	}
+
+	requirements := []string{}
+
+	for _, line := range strings.Split(freezeOut, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			requirements = append(requirements, line)
+		}
+	}
+
+	if len(requirements) == 0 {
+		return requirements, nil
+	}
+
+	requirementsFile, err := os.CreateTemp("", "v-requirements-*.txt")
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	defer os.Remove(requirementsFile.Name())
+
+	requirementsFile.WriteString(strings.Join(requirements, "\n") + "\n")
+	requirementsFile.Close()
+
+	env, err := download.SubprocessEnv()
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	if installOut, err := exec.RunCommandWithOptions([]string{targetInterpreter, "-m", "pip", "install", "-r", requirementsFile.Name()}, state.GetStatePath(), exec.CommandOptions{Env: env}); err != nil {
+		return []string{}, errors.New("Failed to install the packages: " + strings.TrimSpace(installOut))
+	}
+
+	return requirements, nil
+}
+
+// Returns the .python-version files selecting <version>: the nearest one and
+// those found under the configured project roots (see: findProjectReferences),
+// in path order.
+func findVersionFilesSelecting(version string) ([]string, error) {
+	versionFiles := []string{}
+
+	if versionFile, found := SearchForPythonVersionFile(); found && versionFile.Version == version {
+		versionFiles = append(versionFiles, versionFile.Source)
+	}
+
+	config, err := ReadConfig()
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	for _, root := range config.ProjectRoots {
+		references, err := findProjectReferences(root)
+
+		if err != nil {
+			logger.InfoLogger.Println(logger.Yellow("WARNING: Could not search project root " + root + ": " + err.Error()))
+			continue
+		}
+
+		for referencePath, referenced := range references {
+			if path.Base(referencePath) == ".python-version" && referenced == version && !slices.Contains(versionFiles, referencePath) {
+				versionFiles = append(versionFiles, referencePath)
+			}
+		}
+	}
+
+	slices.Sort(versionFiles)
+
+	return versionFiles, nil
+}
+
+// Upgrade (called via `v python upgrade [version]`) moves from <version> (the
+// selected version by default) to the latest patch release of the same minor
+// version: the new release is installed, packages are migrated into it and it
+// replaces the old one as global version, unless they could not be. The
+// .python-version files selecting the old version (the nearest one and those
+// under the configured project roots) are updated with --update-version-file,
+// and the old version is uninstalled with --uninstall-old.
+func upgrade(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+	oldVersion := ""
+
+	if len(positional) > 1 {
+		resolved, err := ResolveAlias(positional[1], currentState.Aliases)
+
+		if err != nil {
+			return err
+		}
+
+		oldVersion = resolved
+	} else {
+		selectedVersion, err := DetermineSelectedPythonVersion(currentState)
+
+		if err != nil {
+			return err
+		}
+
+		if selectedVersion.Source == "system" {
+			return errors.New("No version selected. Pass the version to upgrade.")
+		}
+
+		oldVersion = selectedVersion.Version
+	}
+
+	if err := ValidateVersion(oldVersion); err != nil {
+		return errors.New("Only CPython releases can be upgraded, got " + oldVersion + ".")
+	}
+
+	if !slices.Contains(GetAvailableVersions(), oldVersion) {
+		return errors.New("Python " + oldVersion + " is not installed.")
+	}
+
+	options, err := installOptionsFromFlags(flags)
+
+	if err != nil {
+		return err
+	}
+
+	newVersion, err := findLatestPatch(oldVersion, options.Offline)
+
+	if err != nil {
+		return err
+	}
+
+	if newVersion == oldVersion {
+		logger.InfoLogger.Printf("Python %s is the latest %s release.\n", oldVersion, VersionStringToStruct(oldVersion).MajorMinor())
+		return nil
+	}
+
+	logger.InfoLogger.Printf("Upgrading Python %s to %s\n", oldVersion, logger.Bold(newVersion))
+
+	if !slices.Contains(GetAvailableVersions(), newVersion) {
+		if err := InstallPythonDistribution(newVersion, options); err != nil {
+			return err
+		}
+	}
+
+	migrated, migrationErr := migratePackages(GetInterpreterPath(oldVersion), GetInterpreterPath(newVersion))
+
+	if migrationErr != nil {
+		logger.InfoLogger.Println(logger.Yellow("WARNING: " + migrationErr.Error()))
+	} else {
+		logger.InfoLogger.Printf("Migrated %d packages\n", len(migrated))
+	}
+
+	if currentState.GlobalVersion == oldVersion && migrationErr != nil {
+		// The new version lacks the packages the global one provided.
+		logger.InfoLogger.Printf("Global version kept at %s. Switch once the packages are migrated, with: v python use %s\n", oldVersion, newVersion)
+	} else if currentState.GlobalVersion == oldVersion {
+		state.WriteState(newVersion)
+		logger.InfoLogger.Printf("Global version set to %s\n", newVersion)
+	}
+
+	for _, aliasName := range getAliasesOf(oldVersion, currentState.Aliases) {
+		if currentState.Aliases[aliasName] != oldVersion {
+			continue
+		}
+
+		logger.InfoLogger.Printf("Alias %s still points to %s. Re-point it with: v python alias %s %s\n", aliasName, oldVersion, aliasName, newVersion)
+	}
+
+	if flags.UpdateVersionFile {
+		versionFiles, err := findVersionFilesSelecting(oldVersion)
+
+		if err != nil {
+			return err
+		}
+
+		for _, versionFile := range versionFiles {
+			if err := os.WriteFile(versionFile, []byte(newVersion+"\n"), 0644); err != nil {
+				return err
+			}
+
+			logger.InfoLogger.Printf("Updated %s\n", versionFile)
+		}
+	}
+
+	if flags.UninstallOld && migrationErr != nil {
+		return errors.New("Kept Python " + oldVersion + " since its packages could not be migrated. Uninstall it once they are, with: v python uninstall " + oldVersion)
+	}
+
+	if flags.UninstallOld {
+		// The global version may have changed, and --uninstall-old is explicit consent.
+		flags.Yes = true
+		return uninstallPython([]string{"uninstall", oldVersion}, flags, state.ReadState())
+	}
+
+	return nil
+}
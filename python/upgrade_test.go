@@ -0,0 +1,138 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Sets up a local mirror listing <versions> as the only mirror.
+func setupLocalMirror(t *testing.T, versions []string) {
+	mirrorPath := t.TempDir()
+
+	for _, version := range versions {
+		os.MkdirAll(path.Join(mirrorPath, version), 0750)
+	}
+
+	t.Setenv(mirrorsEnvVar, "file://"+mirrorPath)
+}
+
+func TestFindLatestPatch(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	setupLocalMirror(t, []string{"3.11.7", "3.11.9", "3.11.10", "3.12.0"})
+
+	if latest, err := findLatestPatch("3.11.7", true); err != nil || latest != "3.11.10" {
+		t.Errorf("Expected 3.11.10, got %s (%v)", latest, err)
+	}
+
+	if latest, err := findLatestPatch("3.12.0", true); err != nil || latest != "3.12.0" {
+		t.Errorf("Expected 3.12.0, got %s (%v)", latest, err)
+	}
+}
+
+func TestMigratePackagesInstallsFrozenRequirements(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	requirementsPath := path.Join(t.TempDir(), "requirements")
+	setupScriptedRuntime(t, "3.11.7", "echo 'requests==2.31.0'\necho 'black==24.1.0'\n")
+	setupScriptedRuntime(t, "3.11.9", "cat \"$5\" > "+requirementsPath+"\n")
+
+	migrated, err := migratePackages(GetInterpreterPath("3.11.7"), GetInterpreterPath("3.11.9"))
+
+	if err != nil || len(migrated) != 2 {
+		t.Errorf("Expected 2 packages migrated, got %v (%v)", migrated, err)
+	}
+
+	if content, _ := os.ReadFile(requirementsPath); string(content) != "requests==2.31.0\nblack==24.1.0\n" {
+		t.Errorf("Unexpected requirements installed: %s", content)
+	}
+}
+
+func TestUpgradeMovesToLatestPatch(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	setupLocalMirror(t, []string{"3.11.7", "3.11.9"})
+	setupScriptedRuntime(t, "3.11.7", "exit 0\n")
+	setupScriptedRuntime(t, "3.11.9", "exit 0\n")
+
+	projectPath, _ := os.Getwd()
+	os.WriteFile(path.Join(projectPath, ".python-version"), []byte("3.11.7\n"), 0640)
+	state.WriteState("3.11.7")
+
+	err := upgrade([]string{"upgrade"}, cli.Flags{Offline: true, UpdateVersionFile: true, UninstallOld: true}, state.ReadState())
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if globalVersion := state.ReadState().GlobalVersion; globalVersion != "3.11.9" {
+		t.Errorf("Expected the global version to be 3.11.9, got %s", globalVersion)
+	}
+
+	if content, _ := os.ReadFile(path.Join(projectPath, ".python-version")); strings.TrimSpace(string(content)) != "3.11.9" {
+		t.Errorf("Expected .python-version to be updated, got %s", content)
+	}
+
+	if _, err := os.Stat(state.GetStatePath("runtimes", "python", "3.11.7")); !os.IsNotExist(err) {
+		t.Errorf("Expected the old version to be uninstalled.")
+	}
+}
+
+func TestUpgradeKeepsOldVersionIfMigrationFails(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	setupLocalMirror(t, []string{"3.11.7", "3.11.9"})
+	setupScriptedRuntime(t, "3.11.7", "echo 'requests==2.31.0'\n")
+	setupScriptedRuntime(t, "3.11.9", "exit 1\n")
+	state.WriteState("3.11.7")
+
+	err := upgrade([]string{"upgrade"}, cli.Flags{Offline: true, UninstallOld: true}, state.ReadState())
+
+	if err == nil || !strings.Contains(err.Error(), "Kept Python 3.11.7") {
+		t.Errorf("Expected an error naming the kept version, got %v", err)
+	}
+
+	if !IsCompleteInstall("3.11.7") {
+		t.Errorf("Expected the old version to be kept.")
+	}
+
+	if globalVersion := state.ReadState().GlobalVersion; globalVersion != "3.11.7" {
+		t.Errorf("Expected the global version to stay 3.11.7, got %s", globalVersion)
+	}
+}
+
+func TestUpgradeUpdatesVersionFilesUnderProjectRoots(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	setupLocalMirror(t, []string{"3.11.7", "3.11.9"})
+	setupScriptedRuntime(t, "3.11.7", "exit 0\n")
+	setupScriptedRuntime(t, "3.11.9", "exit 0\n")
+
+	projectRoot := t.TempDir()
+	os.MkdirAll(path.Join(projectRoot, "a"), 0750)
+	os.MkdirAll(path.Join(projectRoot, "b"), 0750)
+	os.WriteFile(path.Join(projectRoot, "a", ".python-version"), []byte("3.11.7\n"), 0640)
+	os.WriteFile(path.Join(projectRoot, "b", ".python-version"), []byte("3.12.0\n"), 0640)
+	os.WriteFile(state.GetStatePath("config.json"), []byte(`{"python": {"projectRoots": ["`+projectRoot+`"]}}`), 0640)
+
+	if err := upgrade([]string{"upgrade", "3.11.7"}, cli.Flags{Offline: true, UpdateVersionFile: true}, state.State{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for project, expected := range map[string]string{"a": "3.11.9", "b": "3.12.0"} {
+		if content, _ := os.ReadFile(path.Join(projectRoot, project, ".python-version")); strings.TrimSpace(string(content)) != expected {
+			t.Errorf("Expected %s/.python-version to select %s, got %s", project, expected, content)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package python
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	cli "v/cli"
+	exec "v/exec"
+	logger "v/logger"
+	state "v/state"
+)
+
+const venvMetadataFilename = ".v-venv.json"
+
+// Metadata written in each virtual environment managed by v.
+type VenvMetadata struct {
+	Name string `json:"name"`
+	// Version the environment was created from, or "system".
+	Runtime     string    `json:"runtime"`
+	Interpreter string    `json:"interpreter"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func GetVenvPath(name string) string {
+	return state.GetStatePath("venvs", name)
+}
+
+func ReadVenvMetadata(name string) (VenvMetadata, error) {
+	return readVenvMetadataAt(GetVenvPath(name))
+}
+
+func readVenvMetadataAt(venvPath string) (VenvMetadata, error) {
+	metadata := VenvMetadata{}
+
+	c, err := os.ReadFile(path.Join(venvPath, venvMetadataFilename))
+
+	if err != nil {
+		return metadata, err
+	}
+
+	err = json.Unmarshal(c, &metadata)
+
+	return metadata, err
+}
+
+// ListVenvs returns the metadata of all virtual environments, in name order.
+// Directories without metadata are skipped.
+func ListVenvs() ([]VenvMetadata, error) {
+	entries, err := os.ReadDir(state.GetStatePath("venvs"))
+
+	if os.IsNotExist(err) {
+		return []VenvMetadata{}, nil
+	}
+
+	if err != nil {
+		return []VenvMetadata{}, err
+	}
+
+	venvs := []VenvMetadata{}
+
+	for _, entry := range entries {
+		if metadata, err := ReadVenvMetadata(entry.Name()); err == nil {
+			venvs = append(venvs, metadata)
+		}
+	}
+
+	return venvs, nil
+}
+
+// Returns whether <venvPath> holds a virtual environment.
+func isVenv(venvPath string) bool {
+	_, err := os.Stat(path.Join(venvPath, "pyvenv.cfg"))
+
+	return err == nil
+}
+
+// Describes the failure of a command from its output (stderr on failure,
+// see: exec.RunCommand) and error.
+func describeCommandFailure(output string, err error) string {
+	if output = strings.TrimSpace(output); output != "" {
+		return err.Error() + "\n" + output
+	}
+
+	return err.Error()
+}
+
+// Returns the version the virtual environment at <venvPath> was created from:
+// the runtime recorded by v if it manages it, otherwise the Python version
+// recorded in its pyvenv.cfg.
+func getVenvVersion(venvPath string) string {
+	if metadata, err := readVenvMetadataAt(venvPath); err == nil {
+		return metadata.Runtime
+	}
+
+	content, _ := os.ReadFile(path.Join(venvPath, "pyvenv.cfg"))
+
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+
+		if found && (key == "version" || key == "version_info") {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
+
+// Returns the interpreter of the virtual environment at <venvPath>.
+func getVenvInterpreterPath(venvPath string) string {
+	return path.Join(venvPath, "bin", "python")
+}
+
+// Returns the names of the virtual environments created from <version>.
+func getVenvsUsing(version string) []string {
+	venvs, _ := ListVenvs()
+	names := []string{}
+
+	for _, venv := range venvs {
+		if venv.Runtime == version {
+			names = append(names, venv.Name)
+		}
+	}
+
+	return names
+}
+
+// Returns the version and interpreter to create a virtual environment from:
+// <requested> (which can be an alias) if set, otherwise the selected version.
+func resolveVenvRuntime(requested string, currentState state.State) (string, string, error) {
+	version := requested
+
+	if version == "" {
+		selectedVersion, err := DetermineSelectedPythonVersion(currentState)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		if selectedVersion.Source == "system" {
+			if selectedVersion.Path == "" {
+				return "", "", errors.New("No version selected and no system Python found. Pass one with --python.")
+			}
+
+			return "system", selectedVersion.Path, nil
+		}
+
+		version = selectedVersion.Version
+	}
+
+	resolved, err := ResolveAlias(version, currentState.Aliases)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if !slices.Contains(GetAvailableVersions(), resolved) {
+		return "", "", errors.New("Python " + resolved + " is not installed.")
+	}
+
+	return resolved, GetInterpreterPath(resolved), nil
+}
+
+func createVenv(args []string, flags cli.Flags, currentState state.State) error {
+	if len(args) < 2 {
+		return errors.New("Missing name of the virtual environment to create.")
+	}
+
+	name := args[1]
+
+	if err := ValidateInstallName(name); err != nil {
+		return err
+	}
+
+	venvPath := GetVenvPath(name)
+
+	if _, err := os.Stat(venvPath); err == nil {
+		return errors.New("Virtual environment " + name + " already exists.")
+	}
+
+	version, interpreterPath, err := resolveVenvRuntime(flags.Python, currentState)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(state.GetStatePath("venvs"), 0775); err != nil {
+		return err
+	}
+
+	if output, err := exec.RunCommand([]string{interpreterPath, "-m", "venv", venvPath}, state.GetStatePath()); err != nil {
+		os.RemoveAll(venvPath)
+		return errors.New("Failed to create virtual environment " + name + " with " + interpreterPath + ": " + describeCommandFailure(output, err))
+	}
+
+	d, _ := json.MarshalIndent(VenvMetadata{Name: name, Runtime: version, Interpreter: interpreterPath, CreatedAt: time.Now().UTC()}, "", "  ")
+
+	if err := os.WriteFile(path.Join(venvPath, venvMetadataFilename), d, 0640); err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Created %s (Python %s) at %s\n", logger.Bold(name), version, venvPath)
+	return nil
+}
+
+func listVenvs(args []string, flags cli.Flags, currentState state.State) error {
+	venvs, err := ListVenvs()
+
+	if err != nil {
+		return err
+	}
+
+	if len(venvs) == 0 {
+		logger.InfoLogger.Println("No virtual environments!")
+		return nil
+	}
+
+	installedVersions := GetAvailableVersions()
+
+	for _, venv := range venvs {
+		line := venv.Name + " (" + venv.Runtime + ")"
+
+		if venv.Runtime != "system" && !slices.Contains(installedVersions, venv.Runtime) {
+			line += logger.Yellow(" (runtime not installed)")
+		}
+
+		logger.InfoLogger.Println(line)
+	}
+
+	return nil
+}
+
+func removeVenv(args []string, flags cli.Flags, currentState state.State) error {
+	if len(args) < 2 {
+		return errors.New("Missing name of the virtual environment to remove.")
+	}
+
+	if _, err := ReadVenvMetadata(args[1]); err != nil || ValidateInstallName(args[1]) != nil {
+		return errors.New("No virtual environment named " + args[1] + ".")
+	}
+
+	if err := os.RemoveAll(GetVenvPath(args[1])); err != nil {
+		return err
+	}
+
+	logger.InfoLogger.Printf("Removed %s\n", args[1])
+	return nil
+}
+
+func whichVenv(args []string, flags cli.Flags, currentState state.State) error {
+	if len(args) < 2 {
+		return errors.New("Missing name of the virtual environment.")
+	}
+
+	if _, err := ReadVenvMetadata(args[1]); err != nil || ValidateInstallName(args[1]) != nil {
+		return errors.New("No virtual environment named " + args[1] + ".")
+	}
+
+	interpreterPath := getVenvInterpreterPath(GetVenvPath(args[1]))
+
+	if flags.RawOutput {
+		logger.InfoLogger.Println(interpreterPath)
+	} else {
+		logger.InfoLogger.Printf("Python path: %s\n", logger.Bold(interpreterPath))
+	}
+
+	return nil
+}
+
+var venvCommands = map[string]func([]string, cli.Flags, state.State) error{
+	"create": createVenv,
+	"ls":     listVenvs,
+	"rm":     removeVenv,
+	"which":  whichVenv,
+}
+
+// Venv (called via `v python venv <create|ls|rm|which>`) manages virtual
+// environments stored under the `venvs` state directory.
+func venv(args []string, flags cli.Flags, currentState state.State) error {
+	positional := cli.Positional(args)
+
+	if len(positional) < 2 {
+		return errors.New("Missing venv command: create, ls, rm or which.")
+	}
+
+	command, found := venvCommands[positional[1]]
+
+	if !found {
+		return errors.New("Unknown venv command: " + positional[1] + ". Expected create, ls, rm or which.")
+	}
+
+	return command(positional[1:], flags, currentState)
+}
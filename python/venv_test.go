@@ -0,0 +1,170 @@
+package python
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	cli "v/cli"
+	state "v/state"
+	testutils "v/testutils"
+)
+
+// Interpreter script creating a minimal virtual environment when called with
+// `-m venv <path>` (see: setupScriptedRuntime).
+const venvScript = "mkdir -p \"$3/bin\" && touch \"$3/bin/python\"\n"
+
+func TestCreateVenvRecordsRuntime(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	setupScriptedRuntime(t, "1.2.3", venvScript)
+
+	currentState := state.State{Aliases: map[string]string{"work": "1.2.3"}}
+
+	if err := venv([]string{"venv", "create", "project"}, cli.Flags{Python: "work"}, currentState); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	metadata, err := ReadVenvMetadata("project")
+
+	if err != nil || metadata.Runtime != "1.2.3" || metadata.Interpreter != GetInterpreterPath("1.2.3") {
+		t.Errorf("Unexpected metadata: %v (%v)", metadata, err)
+	}
+
+	if _, err := os.Stat(path.Join(GetVenvPath("project"), "bin", "python")); err != nil {
+		t.Errorf("Expected the virtual environment to be created.")
+	}
+
+	if err := venv([]string{"venv", "create", "project"}, cli.Flags{Python: "1.2.3"}, currentState); err == nil {
+		t.Errorf("Expected an error for an existing virtual environment.")
+	}
+}
+
+func TestCreateVenvFailsIfRuntimeNotInstalled(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	err := venv([]string{"venv", "create", "project"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	if err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("Expected an error about the missing runtime, got %v", err)
+	}
+}
+
+func TestCreateVenvReportsCommandOutput(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	setupScriptedRuntime(t, "1.2.3", venvScript)
+	os.WriteFile(GetInterpreterPath("1.2.3"), []byte("#!/bin/sh\necho 'No module named ensurepip' >&2\nexit 1\n"), 0777)
+
+	err := venv([]string{"venv", "create", "project"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	if err == nil || !strings.Contains(err.Error(), "No module named ensurepip") {
+		t.Errorf("Expected the command output in the error, got %v", err)
+	}
+}
+
+func TestListVenvsFlagsMissingRuntimes(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	setupScriptedRuntime(t, "1.2.3", venvScript)
+	venv([]string{"venv", "create", "project"}, cli.Flags{Python: "1.2.3"}, state.State{})
+	os.RemoveAll(state.GetStatePath("runtimes", "python", "1.2.3"))
+	out.Reset()
+
+	venv([]string{"venv", "ls"}, cli.Flags{}, state.State{})
+
+	if !strings.Contains(out.String(), "project (1.2.3)") || !strings.Contains(out.String(), "runtime not installed") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+}
+
+func TestUninstallWarnsAboutDependentVenvs(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	setupScriptedRuntime(t, "1.2.3", venvScript)
+	venv([]string{"venv", "create", "project"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	uninstallPython([]string{"uninstall", "1.2.3"}, cli.Flags{Yes: true}, state.State{})
+
+	if !strings.Contains(out.String(), "WARNING") || !strings.Contains(out.String(), "project") {
+		t.Errorf("Expected a warning mentioning project, got %s", out.String())
+	}
+}
+
+func TestRemoveVenv(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	testutils.CaptureOutput(t)
+
+	setupScriptedRuntime(t, "1.2.3", venvScript)
+	venv([]string{"venv", "create", "project"}, cli.Flags{Python: "1.2.3"}, state.State{})
+
+	if err := venv([]string{"venv", "rm", "project"}, cli.Flags{}, state.State{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(GetVenvPath("project")); !os.IsNotExist(err) {
+		t.Errorf("Expected the virtual environment to be removed.")
+	}
+
+	if err := venv([]string{"venv", "rm", "project"}, cli.Flags{}, state.State{}); err == nil {
+		t.Errorf("Expected an error for a missing virtual environment.")
+	}
+}
+
+func TestDetermineSelectedPythonVersionSelectsProjectVenv(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	projectPath, _ := os.Getwd()
+	os.MkdirAll(path.Join(projectPath, ".venv", "bin"), 0750)
+	os.WriteFile(path.Join(projectPath, ".venv", "pyvenv.cfg"), []byte("home = /usr/bin\nversion = 3.12.1\n"), 0640)
+	os.WriteFile(path.Join(projectPath, ".python-version"), []byte("3.11.4"), 0640)
+
+	selectedVersion, err := DetermineSelectedPythonVersion(state.State{})
+
+	if err != nil || selectedVersion.Venv != path.Join(projectPath, ".venv") || selectedVersion.Version != "3.12.1" || selectedVersion.Source != selectedVersion.Venv {
+		t.Errorf("Expected the project venv to be selected, got %v (%v)", selectedVersion, err)
+	}
+}
+
+func TestDetermineSelectedPythonVersionPrefersNearestSelection(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	projectPath, _ := os.Getwd()
+	os.MkdirAll(path.Join(projectPath, ".venv"), 0750)
+	os.WriteFile(path.Join(projectPath, ".venv", "pyvenv.cfg"), []byte("version = 3.12.1\n"), 0640)
+	os.MkdirAll(path.Join(projectPath, "subproject"), 0750)
+	os.WriteFile(path.Join(projectPath, "subproject", ".python-version"), []byte("3.11.4"), 0640)
+	os.Chdir(path.Join(projectPath, "subproject"))
+
+	selectedVersion, err := DetermineSelectedPythonVersion(state.State{})
+
+	if err != nil || selectedVersion.Venv != "" || selectedVersion.Version != "3.11.4" {
+		t.Errorf("Expected the nearest .python-version to be used, got %v (%v)", selectedVersion, err)
+	}
+}
+
+func TestWhichOutputsManagedVenvNamedInPythonVersionFile(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	out := testutils.CaptureOutput(t)
+
+	setupScriptedRuntime(t, "1.2.3", venvScript)
+	venv([]string{"venv", "create", "project"}, cli.Flags{Python: "1.2.3"}, state.State{})
+	out.Reset()
+
+	projectPath, _ := os.Getwd()
+	os.WriteFile(path.Join(projectPath, ".python-version"), []byte("project"), 0640)
+
+	which([]string{"which"}, cli.Flags{RawOutput: true}, state.State{})
+
+	if captured := strings.TrimSpace(out.String()); captured != path.Join(GetVenvPath("project"), "bin", "python") {
+		t.Errorf("Expected the venv interpreter, got %s", captured)
+	}
+}
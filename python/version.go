@@ -1,12 +1,15 @@
 package python
 
 import (
+	"cmp"
 	"errors"
 	"io/ioutil"
 	"os"
 	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
-	exec "v/exec"
 	state "v/state"
 )
 
@@ -16,17 +19,127 @@ func VersionStringToStruct(version string) VersionTag {
 	return VersionTag{Major: splitVersion[0], Minor: splitVersion[1], Patch: splitVersion[2]}
 }
 
+// CompareVersions compares version strings of the form a.b.c numerically,
+// returning -1, 0 or 1 if <a> is respectively lower, equal or greater than <b>.
+func CompareVersions(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for index := 0; index < max(len(aParts), len(bParts)); index++ {
+		var aPart, bPart int
+
+		if index < len(aParts) {
+			aPart, _ = strconv.Atoi(aParts[index])
+		}
+
+		if index < len(bParts) {
+			bPart, _ = strconv.Atoi(bParts[index])
+		}
+
+		if aPart != bPart {
+			return cmp.Compare(aPart, bPart)
+		}
+	}
+
+	return 0
+}
+
+var versionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
 func ValidateVersion(version string) error {
-	if splitVersion := strings.Split(version, "."); len(splitVersion) != 3 {
+	if !versionPattern.MatchString(version) {
 		return errors.New("Invalid version string. Expected format 'a.b.c'.")
 	}
 
 	return nil
 }
 
+// ValidateReleaseVersion checks that <version> names a CPython release (a.b.c)
+// or a release of another implementation (i.e. pypy3.10-7.3.15, graalpy-24.0.0).
+func ValidateReleaseVersion(version string) error {
+	if _, found := ParseImplementationVersion(version); found {
+		return nil
+	}
+
+	if err := ValidateVersion(version); err != nil {
+		return errors.New("Invalid version string. Expected format 'a.b.c', 'pypy<a.b>-<x.y.z>' or 'graalpy-<x.y.z>'.")
+	}
+
+	return nil
+}
+
+// ValidateInstallName checks that <name> can safely be used as the
+// directory name of an install under the runtimes directory.
+func ValidateInstallName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\") || strings.HasPrefix(name, "-") {
+		return errors.New("Invalid name: \"" + name + "\". Names cannot be empty, start with '-' or contain path separators.")
+	}
+
+	return nil
+}
+
+// Matches the versioned interpreter installed by `make altinstall`.
+var interpreterPattern = regexp.MustCompile(`^python\d+\.\d+$`)
+
+// Finds the versioned interpreters (bin/python<major>.<minor>) of the
+// install at <installPath>.
+func findInterpreters(installPath string) []string {
+	entries, _ := os.ReadDir(path.Join(installPath, "bin"))
+	interpreters := []string{}
+
+	for _, entry := range entries {
+		if interpreterPattern.MatchString(entry.Name()) {
+			interpreters = append(interpreters, path.Join(installPath, "bin", entry.Name()))
+		}
+	}
+
+	return interpreters
+}
+
+// Finds the versioned interpreter (bin/python<major>.<minor>) of the install
+// at <installPath>.
+func findInterpreter(installPath string) (string, error) {
+	if interpreters := findInterpreters(installPath); len(interpreters) != 0 {
+		return interpreters[0], nil
+	}
+
+	return "", errors.New("No interpreter found in " + path.Join(installPath, "bin"))
+}
+
+// GetInterpreterPath returns the path to the interpreter of the installed
+// version <version>, as recorded in its manifest. Without a manifest, the
+// interpreter is inferred from the version number or found in the install.
+func GetInterpreterPath(version string) string {
+	installPath := state.GetStatePath("runtimes", "python", version)
+
+	if manifest, err := ReadManifest(version); err == nil && manifest.Executable != "" {
+		if manifest.Prefix != "" {
+			return path.Join(manifest.Prefix, manifest.Executable)
+		}
+
+		return path.Join(installPath, manifest.Executable)
+	}
+
+	if ValidateVersion(version) == nil {
+		tag := VersionStringToStruct(version)
+		return path.Join(installPath, "bin", "python"+tag.MajorMinor())
+	}
+
+	interpreterPath, _ := findInterpreter(installPath)
+
+	return interpreterPath
+}
+
 type SelectedVersion struct {
 	Version string
 	Source  string
+	// Alias the version was selected through, if any.
+	Alias string
+	// Path to the virtual environment selected, if any. Version is then
+	// the version the environment was created from.
+	Venv string
+	// Path to the interpreter, when the system Python is selected.
+	Path string
 }
 
 func ListInstalledVersions() ([]string, error) {
@@ -83,34 +196,108 @@ func SearchForPythonVersionFile() (SelectedVersion, bool) {
 	return SelectedVersion{Version: versionFound, Source: path.Join(currentPath, ".python-version")}, true
 }
 
+// SearchForProjectSelection crawls up to the system root to find either a
+// .venv directory holding a virtual environment, or a .python-version file.
+// The nearest one wins, and a .venv directory wins over a .python-version
+// file in the same directory.
+func SearchForProjectSelection() (SelectedVersion, bool) {
+	currentPath, _ := os.Getwd()
+
+	for {
+		venvPath := path.Join(currentPath, ".venv")
+
+		if isVenv(venvPath) {
+			return SelectedVersion{Version: getVenvVersion(venvPath), Source: venvPath, Venv: venvPath}, true
+		}
+
+		versionFilePath := path.Join(currentPath, ".python-version")
+
+		if content, err := os.ReadFile(versionFilePath); err == nil {
+			return SelectedVersion{Version: strings.TrimSpace(string(content)), Source: versionFilePath}, true
+		}
+
+		nextPath := path.Dir(currentPath)
+
+		if currentPath == nextPath {
+			return SelectedVersion{}, false
+		}
+
+		currentPath = nextPath
+	}
+}
+
+// Environment variable overriding the selected version (see: `v python exec`).
+const versionEnvVar = "V_PYTHON_VERSION"
+
 // DetermineSelectedPythonVersion returns the Python runtime version that should be
 // used according to v.
 //
-// First, v will look in the current directory and all its parents for a .python-version
-// file that would indicate which version is preferred. If none are found, the global
+// The V_PYTHON_VERSION environment variable, if set, takes precedence. Otherwise,
+// v will look in the current directory and all its parents for a .venv virtual
+// environment or a .python-version file that would indicate which version
+// (or v-managed virtual environment) is preferred. If none are found, the global
 // user-defined version (via `v use <version>`) is used. If there is none, the system
-// Python version is used.
+// Python version is used. Aliases (see: `v python alias`) are resolved.
 func DetermineSelectedPythonVersion(currentState state.State) (SelectedVersion, error) {
-	pythonFileVersion, pythonFileVersionFound := SearchForPythonVersionFile()
+	if envVersion := strings.TrimSpace(os.Getenv(versionEnvVar)); envVersion != "" {
+		return resolveSelectedVersion(SelectedVersion{Version: envVersion, Source: versionEnvVar}, currentState.Aliases)
+	}
+
+	projectVersion, projectVersionFound := SearchForProjectSelection()
+
+	if projectVersionFound && projectVersion.Venv != "" {
+		return projectVersion, nil
+	}
 
-	if pythonFileVersionFound {
-		return pythonFileVersion, nil
+	if projectVersionFound {
+		return resolveSelectedVersion(projectVersion, currentState.Aliases)
 	}
 
 	if len(currentState.GlobalVersion) != 0 {
-		return SelectedVersion{Version: currentState.GlobalVersion, Source: state.GetStatePath("state.json")}, nil
+		return resolveSelectedVersion(SelectedVersion{Version: currentState.GlobalVersion, Source: state.GetStatePath("state.json")}, currentState.Aliases)
+	}
+
+	systemVersion, systemPath := DetermineSystemPython()
+	return SelectedVersion{Source: "system", Version: systemVersion, Path: systemPath}, nil
+}
+
+// Resolves the alias <selected> may have been selected through. Names that
+// are neither aliases nor installed versions but name a v-managed virtual
+// environment select that environment.
+func resolveSelectedVersion(selected SelectedVersion, aliases map[string]string) (SelectedVersion, error) {
+	resolved, err := ResolveAlias(selected.Version, aliases)
+
+	if err != nil {
+		return selected, err
+	}
+
+	if resolved == selected.Version && !slices.Contains(GetAvailableVersions(), resolved) {
+		if metadata, err := ReadVenvMetadata(resolved); err == nil && ValidateInstallName(resolved) == nil {
+			selected.Venv = GetVenvPath(resolved)
+			selected.Version = metadata.Runtime
+			return selected, nil
+		}
 	}
 
-	systemVersion, _ := DetermineSystemPython()
-	return SelectedVersion{Source: "system", Version: systemVersion}, nil
+	if resolved != selected.Version {
+		selected.Alias = selected.Version
+		selected.Version = resolved
+	}
+
+	return selected, nil
 }
 
-// DetermineSystemPython returns the unshimmed Python version and path.
-// It assumes that /bin/python is where system Python lives.
+// DetermineSystemPython returns the unshimmed Python version and path: the
+// first interpreter found on PATH or in common prefixes, excluding v's shims
+// (see: DiscoverSystemInterpreters). Empty values are returned if none is found.
 func DetermineSystemPython() (string, string) {
-	versionOut, _ := exec.RunCommand([]string{"/bin/python", "--version"}, state.GetStatePath())
-	detectedVersion, _ := strings.CutPrefix(versionOut, "Python")
-	return strings.TrimSpace(detectedVersion), "/bin/python"
+	interpreters := findSystemInterpreters(true)
+
+	if len(interpreters) == 0 {
+		return "", ""
+	}
+
+	return interpreters[0].Version, interpreters[0].Path
 }
 
 // Gets all install versions.
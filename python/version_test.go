@@ -15,20 +15,19 @@ import (
 func TestDetermineSystemPythonGetsUnshimmedPythonRuntime(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
+	os.MkdirAll(state.GetStatePath("shims"), 0750)
 	ioutil.WriteFile(state.GetStatePath("shims", "python"), []byte("#!/bin/bash\necho \"Python 4.5.6\""), 0777)
-	mockSystemPythonPath := t.TempDir()
-	mockSystemPythonExecPath := path.Join(mockSystemPythonPath, "python")
+	mockSystemPythonPath := setupSystemInterpreters(t, map[string]string{"python3": "3.12.1"})
+	mockSystemPythonExecPath := path.Join(mockSystemPythonPath, "python3")
 
-	oldPath := os.Getenv("PATH")
-	os.Setenv("PATH", fmt.Sprintf("%s:/usr/bin", state.GetStatePath("shims")))
-	defer os.Setenv("PATH", oldPath)
+	os.Setenv("PATH", fmt.Sprintf("%s:%s", state.GetStatePath("shims"), mockSystemPythonPath))
 	sysVersion, sysPath := DetermineSystemPython()
 
 	if sysVersion == "4.5.6" {
 		t.Errorf("Expected system Python to not match the shim, found %s instead.", sysVersion)
 	}
 
-	if sysPath != "/bin/python" {
+	if sysPath != mockSystemPythonExecPath {
 		t.Errorf("Expected system Python path to be %s, found %s instead.", mockSystemPythonExecPath, sysPath)
 	}
 }
@@ -79,6 +78,19 @@ func TestDetermineSelectedPythonVersionDefaultsToSystem(t *testing.T) {
 	}
 }
 
+func TestDetermineSelectedPythonVersionHonoursEnvironment(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	t.Setenv("V_PYTHON_VERSION", "work")
+	currentState := state.State{GlobalVersion: "1.2.4", Aliases: map[string]string{"work": "1.2.3"}}
+
+	selectedVersion, err := DetermineSelectedPythonVersion(currentState)
+
+	if err != nil || selectedVersion.Version != "1.2.3" || selectedVersion.Alias != "work" {
+		t.Errorf("Expected V_PYTHON_VERSION to select 1.2.3 through work, got %v (%v)", selectedVersion, err)
+	}
+}
+
 func TestSearchForPythonVersionFileFindsFileInCwd(t *testing.T) {
 	defer testutils.SetupAndCleanupEnvironment(t)()
 
@@ -174,3 +186,64 @@ func TestListInstalledVersionNoRuntimesDir(t *testing.T) {
 		t.Errorf("Expected error to be returned, got nil.")
 	}
 }
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"3.10.0", "3.9.18", 1},
+		{"3.9.18", "3.10.0", -1},
+		{"3.11.4", "3.11.4", 0},
+		{"3.11", "3.11.0", 0},
+	}
+
+	for _, c := range cases {
+		if result := CompareVersions(c.a, c.b); result != c.expected {
+			t.Errorf("Expected CompareVersions(%s, %s) to be %d, got %d", c.a, c.b, c.expected, result)
+		}
+	}
+}
+
+func TestValidateInstallName(t *testing.T) {
+	for _, name := range []string{"main-20261018", "3.11.4", "pr-1234"} {
+		if err := ValidateInstallName(name); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"", ".", "..", "../etc", "a/b", "--all"} {
+		if err := ValidateInstallName(name); err == nil {
+			t.Errorf("Expected %s to be invalid.", name)
+		}
+	}
+}
+
+func TestGetInterpreterPathUsesManifest(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.MkdirAll(state.GetStatePath("runtimes", "python", "main-20261018", "bin"), 0750)
+	WriteManifest("main-20261018", Manifest{Executable: "bin/python3.14"})
+
+	interpreterPath := GetInterpreterPath("main-20261018")
+	expected := state.GetStatePath("runtimes", "python", "main-20261018", "bin", "python3.14")
+
+	if interpreterPath != expected {
+		t.Errorf("Expected %s, got %s", expected, interpreterPath)
+	}
+}
+
+func TestGetInterpreterPathFindsInterpreterWithoutManifest(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	binPath := state.GetStatePath("runtimes", "python", "dev", "bin")
+	os.MkdirAll(binPath, 0750)
+	for _, name := range []string{"python3", "python3.14-config", "python3.14"} {
+		os.WriteFile(path.Join(binPath, name), []byte(""), 0750)
+	}
+
+	if interpreterPath := GetInterpreterPath("dev"); interpreterPath != path.Join(binPath, "python3.14") {
+		t.Errorf("Unexpected interpreter path: %s", interpreterPath)
+	}
+}
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// User configuration, stored as JSON in config.json under the state root.
+// Configuration is organized in sections (i.e. one per runtime) so that each
+// consumer can define the shape of its own settings.
+type Config map[string]json.RawMessage
+
+func ReadConfig() (Config, error) {
+	config := Config{}
+
+	c, err := os.ReadFile(GetStatePath("config.json"))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(c, &config); err != nil {
+		return config, errors.New("Invalid configuration file (" + GetStatePath("config.json") + "): " + err.Error())
+	}
+
+	return config, nil
+}
+
+// ReadConfigSection decodes the configuration section <section> into target.
+// If the section is absent, target is left untouched so that defaults set by the
+// caller are preserved.
+func ReadConfigSection(section string, target any) error {
+	config, err := ReadConfig()
+
+	if err != nil {
+		return err
+	}
+
+	raw, found := config[section]
+
+	if !found {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, target); err != nil {
+		return errors.New("Invalid configuration section \"" + section + "\": " + err.Error())
+	}
+
+	return nil
+}
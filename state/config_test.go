@@ -0,0 +1,52 @@
+package state
+
+import (
+	"os"
+	"testing"
+	testutils "v/testutils"
+)
+
+type mockSection struct {
+	Value   string `json:"value"`
+	Default string `json:"default"`
+}
+
+func TestReadConfigSectionDecodesSection(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(GetStatePath("config.json"), []byte(`{"mock": {"value": "test"}}`), 0750)
+
+	section := mockSection{Default: "kept"}
+
+	if err := ReadConfigSection("mock", &section); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if section.Value != "test" || section.Default != "kept" {
+		t.Errorf("Unexpected section content: %v", section)
+	}
+}
+
+func TestReadConfigSectionWithoutConfigFileKeepsDefaults(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	section := mockSection{Default: "kept"}
+
+	if err := ReadConfigSection("mock", &section); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if section.Default != "kept" {
+		t.Errorf("Unexpected section content: %v", section)
+	}
+}
+
+func TestReadConfigSectionReturnsErrorOnInvalidConfig(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	os.WriteFile(GetStatePath("config.json"), []byte(`{"mock": `), 0750)
+
+	if err := ReadConfigSection("mock", &mockSection{}); err == nil {
+		t.Errorf("Expected error, got nil.")
+	}
+}
@@ -11,6 +11,9 @@ import (
 // between calls.
 type State struct {
 	GlobalVersion string `json:"globalVersion"`
+	// Alternative names for versions (i.e. "work": "3.11.4"), which can
+	// also point to other aliases.
+	Aliases map[string]string `json:"aliases,omitempty"`
 }
 
 func GetStatePath(pathSegments ...string) string {
@@ -45,9 +48,20 @@ func ReadState() State {
 	return state
 }
 
+// WriteState sets the global version, preserving the rest of the state.
 func WriteState(version string) {
-	state := State{GlobalVersion: version}
+	state := ReadState()
+	state.GlobalVersion = version
 
-	d, _ := json.Marshal(state)
-	ioutil.WriteFile(GetStatePath("state.json"), d, 0750)
+	SaveState(state)
+}
+
+func SaveState(state State) error {
+	d, err := json.Marshal(state)
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(GetStatePath("state.json"), d, 0750)
 }
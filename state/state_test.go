@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"reflect"
 	"testing"
 	testutils "v/testutils"
 )
@@ -43,7 +44,7 @@ func TestReadStateReadsStateAtPath(t *testing.T) {
 
 	readState := ReadState()
 
-	if readState != mockState {
+	if !reflect.DeepEqual(readState, mockState) {
 		t.Errorf("Did not find expected state. %v != %v", mockState, readState)
 	}
 }
@@ -62,11 +63,24 @@ func TestWriteStateWritesAtPath(t *testing.T) {
 	bytes, _ := ioutil.ReadFile(statePath)
 	json.Unmarshal(bytes, &readState)
 
-	if readState != mockState {
+	if !reflect.DeepEqual(readState, mockState) {
 		t.Errorf("Did not find expected state. %v != %v", mockState, readState)
 	}
 }
 
+func TestWriteStatePreservesAliases(t *testing.T) {
+	defer testutils.SetupAndCleanupEnvironment(t)()
+
+	SaveState(State{GlobalVersion: "1.0.0", Aliases: map[string]string{"work": "1.0.0"}})
+	WriteState("2.0.0")
+
+	readState := ReadState()
+
+	if readState.GlobalVersion != "2.0.0" || readState.Aliases["work"] != "1.0.0" {
+		t.Errorf("Expected global version to be updated and aliases kept, got %v", readState)
+	}
+}
+
 func TestEnsureStatePath(t *testing.T) {
 	// EnsureStatePath returns an error if the given path doesn't exist.
 	defer testutils.SetupAndCleanupEnvironment(t)()
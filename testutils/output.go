@@ -0,0 +1,19 @@
+package testutils
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	logger "v/logger"
+)
+
+// CaptureOutput redirects the info logger to a buffer for the duration of the
+// test and returns the buffer.
+func CaptureOutput(t *testing.T) *bytes.Buffer {
+	var out bytes.Buffer
+
+	logger.InfoLogger.SetOutput(&out)
+	t.Cleanup(func() { logger.InfoLogger.SetOutput(os.Stdout) })
+
+	return &out
+}
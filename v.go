@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	cache "v/cache"
 	cli "v/cli"
 	commands "v/commands"
 	python "v/python"
@@ -17,11 +18,14 @@ const (
 // Main entrypoint.
 func main() {
 	args := os.Args[1:]
+	python.ToolVersion = Version
 	currentState := state.ReadState()
 
 	root := cli.Namespace{Label: ""}
 	root.AddCommand(
 		"init", commands.Initialize, "v init", "Initializes the v state.",
+	).AddCommand(
+		"import", python.ImportVersions, "v import <pyenv|asdf> [--move] [--dry-run]", "Imports the Python versions installed by pyenv or asdf.",
 	)
 
 	cli := cli.CLI{
@@ -30,7 +34,7 @@ func main() {
 		},
 	}
 
-	cli.AddNamespace(root).AddNamespace(python.GetNamespace())
+	cli.AddNamespace(root).AddNamespace(python.GetNamespace()).AddNamespace(cache.GetNamespace())
 
 	err := cli.Run(args, currentState)
 